@@ -62,3 +62,32 @@ func (a Integer) Compare(b Item) int {
 		return 0
 	}
 }
+
+// wrapped is an Item holding an arbitrary value ordered by a less function
+type wrapped[T any] struct {
+	value T
+	less  func(a, b T) bool
+}
+
+func (a *wrapped[T]) Compare(b Item) int {
+	other := b.(*wrapped[T])
+	switch {
+	case a.less(a.value, other.value):
+		return -1
+	case a.less(other.value, a.value):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Wrap returns an Item holding value and ordered by less,
+// so any type can be used in a heap without defining a named Item type.
+func Wrap[T any](value T, less func(a, b T) bool) Item {
+	return &wrapped[T]{value: value, less: less}
+}
+
+// Unwrap returns the value held by an Item created with Wrap.
+func Unwrap[T any](item Item) T {
+	return item.(*wrapped[T]).value
+}
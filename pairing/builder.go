@@ -0,0 +1,30 @@
+package pairing
+
+import (
+	heap "github.com/theodesp/go-heaps"
+)
+
+// MinBuilder feeds items into a PairHeap while reporting the running
+// minimum after every addition.
+//
+// Structure is not thread safe.
+type MinBuilder struct {
+	heap *PairHeap
+}
+
+// NewMinBuilder returns a MinBuilder adding its items to p.
+func NewMinBuilder(p *PairHeap) *MinBuilder {
+	return &MinBuilder{heap: p}
+}
+
+// Add inserts item and returns the minimum of all the items added so far.
+// The complexity is O(1).
+func (b *MinBuilder) Add(item heap.Item) heap.Item {
+	b.heap.Insert(item)
+	return b.heap.FindMin()
+}
+
+// Heap returns the PairHeap holding the added items.
+func (b *MinBuilder) Heap() *PairHeap {
+	return b.heap
+}
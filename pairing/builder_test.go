@@ -0,0 +1,19 @@
+package pairing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theodesp/go-heaps"
+)
+
+func TestMinBuilder(t *testing.T) {
+	b := NewMinBuilder(New())
+
+	var mins []go_heaps.Item
+	for _, v := range []int{7, 9, 4, 6, 4, 1, 3} {
+		mins = append(mins, b.Add(Int(v)))
+	}
+	assert.Equal(t, []go_heaps.Item{Int(7), Int(7), Int(4), Int(4), Int(4), Int(1), Int(1)}, mins)
+	assert.Equal(t, 7, b.Heap().Size())
+}
@@ -0,0 +1,82 @@
+package pairing
+
+import (
+	heap "github.com/theodesp/go-heaps"
+)
+
+// Element is a handle to an item inserted with InsertElement. It refers to
+// the node holding the item, so it stays valid while the node moves around
+// the heap (including into another heap through Meld) and lets the item be
+// deleted or adjusted without searching for it. The handle is invalidated
+// once its item leaves the heap.
+type Element struct {
+	node *node
+}
+
+// Item returns the item e refers to, or nil if it is no longer in a heap.
+func (e *Element) Item() heap.Item {
+	if e.node == nil {
+		return nil
+	}
+	return e.node.item
+}
+
+// invalidate detaches n from its handle, if it has one
+func (n *node) invalidate() {
+	if n.elem != nil {
+		n.elem.node = nil
+		n.elem = nil
+	}
+}
+
+// InsertElement inserts the value to the PairHeap and returns a handle to it.
+// The complexity is O(1).
+func (p *PairHeap) InsertElement(v heap.Item) *Element {
+	n := p.insertItem(v)
+	n.elem = &Element{node: n}
+	p.hasElements = true
+	return n.elem
+}
+
+// DeleteElement removes the item referred to by e from the PairHeap and
+// returns it, or nil if e is no longer valid. e must come from p or from a
+// heap melded into p.
+// The complexity is O(k + log n) amortized when the parent of the item has
+// k children, as unlinking it scans them; k is n-1 in the worst case, e.g.
+// after ascending insertions.
+func (p *PairHeap) DeleteElement(e *Element) heap.Item {
+	if e.node == nil {
+		return nil
+	}
+	if p.clock != nil {
+		defer p.timeOp(&p.timings.Delete, p.clock())
+	}
+	n := e.node
+	p.remove(n)
+	p.metrics.Deletes++
+	p.checkEmpty()
+	return n.item
+}
+
+// AdjustElement replaces the item referred to by e with new and returns it,
+// or nil if e is no longer valid. e stays valid and now refers to new.
+// e must come from p or from a heap melded into p.
+// The complexity is O(k) when new does not compare above the old item and
+// O(k + log n) amortized otherwise, for k children of the parent of the
+// item as in DeleteElement.
+func (p *PairHeap) AdjustElement(e *Element, new heap.Item) heap.Item {
+	n := e.node
+	if n == nil {
+		return nil
+	}
+	if n != p.root && p.max == nil && p.compare(new, n.item) <= 0 {
+		// decrease key: cut the subtree, it stays heap ordered below new
+		defer p.holdNewMin(nil)()
+		n.detach()
+		n.item = new
+		p.merge(&p.root, n)
+		return n.item
+	}
+	p.replace(n, new)
+	return n.item
+}
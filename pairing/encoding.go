@@ -0,0 +1,170 @@
+package pairing
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	heap "github.com/theodesp/go-heaps"
+)
+
+// ToParentArray encodes the tree of the PairHeap as its items in preorder
+// along with, for each item, the index of its parent (-1 for the root).
+// Siblings keep their relative order.
+// The complexity is O(n).
+func (p *PairHeap) ToParentArray() (items []heap.Item, parents []int) {
+	if p.IsEmpty() {
+		return nil, nil
+	}
+	nodes := p.root.collect(nil)
+	index := make(map[*node]int, len(nodes))
+	items = make([]heap.Item, len(nodes))
+	parents = make([]int, len(nodes))
+	for i, n := range nodes {
+		index[n] = i
+		items[i] = n.item
+		parents[i] = -1
+		if n.parent != nil {
+			parents[i] = index[n.parent]
+		}
+	}
+	return items, parents
+}
+
+// FromParentArray rebuilds the PairHeap encoded by ToParentArray. It fails
+// unless parents describes a single rooted tree in which no item is smaller
+// than its parent.
+// The complexity is O(n).
+func FromParentArray(items []heap.Item, parents []int) (*PairHeap, error) {
+	return fromParentArray(New(), items, parents)
+}
+
+// fromParentArray rebuilds the tree encoded by ToParentArray into the empty
+// heap p, validating it with the comparator of p
+func fromParentArray(p *PairHeap, items []heap.Item, parents []int) (*PairHeap, error) {
+	if len(items) != len(parents) {
+		return nil, fmt.Errorf("pairing: %d items but %d parents", len(items), len(parents))
+	}
+	if len(items) == 0 {
+		return p, nil
+	}
+	nodes := make([]*node, len(items))
+	for i, item := range items {
+		nodes[i] = &node{item: item, seq: i}
+	}
+	root := -1
+	for i, parent := range parents {
+		switch {
+		case parent == -1:
+			if root != -1 {
+				return nil, fmt.Errorf("pairing: items %d and %d are both roots", root, i)
+			}
+			root = i
+		case parent < 0 || parent >= len(nodes) || parent == i:
+			return nil, fmt.Errorf("pairing: item %d has invalid parent %d", i, parent)
+		default:
+			if p.compare(items[i], items[parent]) < 0 {
+				return nil, fmt.Errorf("pairing: item %d is smaller than its parent %d", i, parent)
+			}
+			nodes[i].parent = nodes[parent]
+			nodes[parent].children = append(nodes[parent].children, nodes[i])
+		}
+	}
+	if root == -1 {
+		return nil, fmt.Errorf("pairing: no root item")
+	}
+	// every node linked below the root means there is no detached cycle
+	if reached := len(nodes[root].collect(nil)); reached != len(nodes) {
+		return nil, fmt.Errorf("pairing: %d items are not connected to the root", len(nodes)-reached)
+	}
+	p.root = nodes[root]
+	p.size = len(nodes)
+	p.seq = len(nodes)
+	return p, nil
+}
+
+// DumpStructure writes the exact tree of the PairHeap to w: the number of
+// items followed, for each item in the preorder of ToParentArray, by the
+// index of its parent and the item encoded by enc. The output only depends
+// on the tree, so golden dumps expose any change in how it is built.
+// The tree can be read back with LoadStructure.
+// The complexity is O(n).
+func (p *PairHeap) DumpStructure(w io.Writer, enc func(item heap.Item) []byte) error {
+	items, parents := p.ToParentArray()
+	buf := binary.AppendUvarint(nil, uint64(len(items)))
+	if _, err := w.Write(buf); err != nil {
+		return err
+	}
+	for i, item := range items {
+		data := enc(item)
+		buf = binary.AppendVarint(buf[:0], int64(parents[i]))
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		if _, err := w.Write(append(buf, data...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadStructure reads a tree written by DumpStructure from r, decoding the
+// items with dec, and returns a PairHeap with the very same tree ordered by
+// cmp, or by Item.Compare if cmp is nil. It fails like FromParentArray if
+// the tree is not a valid heap under that order.
+// The complexity is O(n).
+func LoadStructure(r io.Reader, dec func(data []byte) (heap.Item, error), cmp func(a, b heap.Item) int) (*PairHeap, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		buffered := bufio.NewReader(r)
+		br, r = buffered, buffered
+	}
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	var items []heap.Item
+	var parents []int
+	for i := uint64(0); i < count; i++ {
+		parent, err := binary.ReadVarint(br)
+		if err != nil {
+			return nil, err
+		}
+		l, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, err
+		}
+		data, err := readData(r, l)
+		if err != nil {
+			return nil, err
+		}
+		item, err := dec(data)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		parents = append(parents, int(parent))
+	}
+	p := New()
+	if cmp != nil {
+		p = NewWithComparator(cmp)
+	}
+	return fromParentArray(p, items, parents)
+}
+
+// readData reads l bytes from r, growing the buffer as the data arrives so
+// that a corrupt length fails on the missing data instead of allocating it
+func readData(r io.Reader, l uint64) ([]byte, error) {
+	if l > math.MaxInt64 {
+		return nil, fmt.Errorf("pairing: invalid length %d", l)
+	}
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r, int64(l)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,113 @@
+package pairing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theodesp/go-heaps"
+)
+
+func TestParentArrayRoundTrip(t *testing.T) {
+	items, parents := New().ToParentArray()
+	assert.Empty(t, items)
+	assert.Empty(t, parents)
+
+	p := New()
+	for _, v := range []int{5, 3, 8, 1, 9, 4, 7} {
+		p.Insert(Int(v))
+	}
+	p.DeleteMin()
+
+	items, parents = p.ToParentArray()
+	assert.Len(t, items, 6)
+	assert.Equal(t, -1, parents[0])
+	assert.Equal(t, p.FindMin(), items[0])
+
+	q, err := FromParentArray(items, parents)
+	assert.NoError(t, err)
+	assert.Equal(t, p.Size(), q.Size())
+	items2, parents2 := q.ToParentArray()
+	assert.Equal(t, items, items2)
+	assert.Equal(t, parents, parents2)
+
+	got, _ := q.Page(6)
+	assert.Equal(t, []go_heaps.Item{Int(3), Int(4), Int(5), Int(7), Int(8), Int(9)}, got)
+}
+
+func TestFromParentArrayInvalid(t *testing.T) {
+	items := []go_heaps.Item{Int(1), Int(2), Int(3)}
+	for _, parents := range [][]int{
+		{-1, 0},     // length mismatch
+		{-1, -1, 0}, // two roots
+		{0, 0, 1},   // no root
+		{-1, 0, 5},  // out of range
+		{1, -1, 1},  // 1 is smaller than its parent 2
+	} {
+		_, err := FromParentArray(items, parents)
+		assert.Error(t, err, parents)
+	}
+
+	// cycle detached from the root
+	_, err := FromParentArray([]go_heaps.Item{Int(1), Int(2), Int(2)}, []int{-1, 2, 1})
+	assert.Error(t, err)
+}
+
+func TestStructureRoundTrip(t *testing.T) {
+	enc := func(item go_heaps.Item) []byte {
+		data, _ := encodeInt(item)
+		return data
+	}
+	p := New()
+	for _, v := range []int{5, 3, 8, 1, 9, 4, 7, 3} {
+		p.Insert(Int(v))
+	}
+	p.DeleteMin()
+
+	var buf bytes.Buffer
+	assert.NoError(t, p.DumpStructure(&buf, enc))
+	dump := buf.Bytes()
+	q, err := LoadStructure(bytes.NewReader(dump), decodeInt, nil)
+	assert.NoError(t, err)
+	assert.True(t, p.StructurallyEqual(q))
+
+	var again bytes.Buffer
+	assert.NoError(t, q.DumpStructure(&again, enc))
+	assert.Equal(t, dump, again.Bytes())
+
+	_, err = LoadStructure(bytes.NewReader(dump[:len(dump)-1]), decodeInt, nil)
+	assert.Error(t, err)
+
+	// a single root claiming a huge item
+	for _, l := range []uint64{1 << 40, math.MaxUint64} {
+		corrupt := binary.AppendUvarint(nil, 1)
+		corrupt = binary.AppendVarint(corrupt, -1)
+		corrupt = binary.AppendUvarint(corrupt, l)
+		_, err = LoadStructure(bytes.NewReader(corrupt), decodeInt, nil)
+		assert.Error(t, err)
+	}
+
+	// heaps with their own comparator load back with it
+	max := NewWithComparator(reverse)
+	for _, v := range []int{5, 3, 8, 1, 9} {
+		max.Insert(Int(v))
+	}
+	max.DeleteMin()
+	buf.Reset()
+	assert.NoError(t, max.DumpStructure(&buf, enc))
+	dump = buf.Bytes()
+	_, err = LoadStructure(bytes.NewReader(dump), decodeInt, nil)
+	assert.Error(t, err)
+	q, err = LoadStructure(bytes.NewReader(dump), decodeInt, reverse)
+	assert.NoError(t, err)
+	assert.True(t, max.StructurallyEqual(q))
+	assert.Equal(t, Int(8), q.FindMin())
+
+	buf.Reset()
+	assert.NoError(t, New().DumpStructure(&buf, enc))
+	q, err = LoadStructure(&buf, decodeInt, nil)
+	assert.NoError(t, err)
+	assert.True(t, q.IsEmpty())
+}
@@ -6,13 +6,49 @@
 package pairing
 
 import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync/atomic"
+	"time"
+
 	heap "github.com/theodesp/go-heaps"
 )
 
 // PairHeap is an implementation of a Pairing Heap.
 // The zero value for PairHeap Root is an empty Heap.
 type PairHeap struct {
-	root       *node
+	root *node
+	size int
+	// sequence number handed to the next inserted item
+	seq int
+	// orders the items, Item.Compare unless given to NewWithComparator
+	cmp func(a, b heap.Item) int
+	// identifies cmp for Meld: zero for Item.Compare, otherwise shared only
+	// by the heaps derived from the same NewWithComparator call
+	cmpID uint64
+	// maximum number of children per node, unbounded if zero
+	maxChildren int
+	// degeneration hook and the operations left until it is checked again
+	onDegenerate func(depth, size int)
+	untilCheck   int
+	// called when a delete takes the last item
+	onEmpty func()
+	// called when the root changes, nil while held by holdNewMin
+	onNewMin func(old, new heap.Item)
+	// max-ordered heap mirroring the items when WithMaxTracking is set
+	trackMax bool
+	max      *PairHeap
+	// where Spill writes the items beyond its threshold, if configured
+	spill *spill
+	// clock timing the operations when WithClock is set
+	clock   func() time.Time
+	timings OpTimings
+	// whether InsertElement handles may point into the heap
+	hasElements bool
+	// cumulative operation counters
+	metrics          Metrics
+	countComparisons bool
 }
 
 // node contains the current item and the list if the sub-heaps
@@ -23,43 +59,44 @@ type node struct {
 	children []*node
 	// A reference to the parent Heap Node
 	parent *node
+	// Insertion sequence number of the item
+	seq int
+	// The node holding the same item in the max-ordered heap, if tracked
+	twin *node
+	// The handle given out by InsertElement, if any
+	elem *Element
 }
 
-func (n *node) detach() []*node {
+// detach unlinks n from its parent, keeping its own subtree intact
+func (n *node) detach() {
 	if n.parent == nil {
-		return nil // avoid detaching root
-	}
-	for _, node := range n.children {
-		node.parent = n.parent
+		return // avoid detaching root
 	}
-	var idx int
 	for i, node := range n.parent.children {
 		if node == n {
-			idx = i
+			n.parent.children = append(n.parent.children[:i], n.parent.children[i+1:]...)
 			break
 		}
 	}
-	n.parent.children = append(n.parent.children[:idx], n.parent.children[idx+1:]...)
 	n.parent = nil
-	return n.children
 }
 
-func (n *node) findNode(item heap.Item) *node {
-	if n.item.Compare(item) == 0 {
+func (n *node) findNode(item heap.Item, cmp func(a, b heap.Item) int) *node {
+	if cmp(n.item, item) == 0 {
 		return n
 	} else {
-		return n.findInChildren(n.children, item)
+		return n.findInChildren(n.children, item, cmp)
 	}
 }
 
-func (n *node) findInChildren(children []*node, item heap.Item) *node {
+func (n *node) findInChildren(children []*node, item heap.Item, cmp func(a, b heap.Item) int) *node {
 	if len(children) == 0 {
 		return nil
 	}
 	var node *node
 loop:
 	for _, child := range children {
-		node = child.findNode(item)
+		node = child.findNode(item, cmp)
 		if node != nil {
 			break loop
 		}
@@ -69,12 +106,121 @@ loop:
 
 // Init initializes or clears the PairHeap
 func (p *PairHeap) Init() *PairHeap {
+	if p.cmp == nil {
+		p.cmp, p.cmpID = compareItems, 0
+	}
+	p.invalidateAll()
+	p.hasElements = false
 	p.root = &node{}
+	p.size = 0
+	p.seq = 0
+	p.untilCheck = 0
+	if p.trackMax {
+		p.max = newHeap(p.reverse, p.cmpID, nil)
+	}
+	return p
+}
+
+// New returns an initialized PairHeap configured with the given options.
+func New(opts ...Option) *PairHeap {
+	return newHeap(compareItems, 0, opts)
+}
+
+// lastCmpID is the comparator id handed out by the last NewWithComparator
+var lastCmpID uint64
+
+// NewWithComparator returns an initialized PairHeap ordering its items by cmp
+// instead of Item.Compare, e.g. to build a max-heap. cmp must follow the same
+// contract as Item.Compare.
+// Only the heap returned and the heaps made from it, e.g. with NewEmpty, can
+// be melded together, as different funcs cannot be told apart reliably.
+func NewWithComparator(cmp func(a, b heap.Item) int, opts ...Option) *PairHeap {
+	return newHeap(cmp, atomic.AddUint64(&lastCmpID, 1), opts)
+}
+
+// NewEmpty returns an empty PairHeap ordered like p, which can be melded with
+// it, configured with the given options.
+func (p *PairHeap) NewEmpty(opts ...Option) *PairHeap {
+	return newHeap(p.cmp, p.cmpID, opts)
+}
+
+func newHeap(cmp func(a, b heap.Item) int, cmpID uint64, opts []Option) *PairHeap {
+	p := &PairHeap{cmp: cmp, cmpID: cmpID}
+	// options are applied before Init so they survive later Init calls
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p.Init()
+}
+
+// FromSorted returns a PairHeap holding the given items, which must already
+// be sorted in ascending order. Unlike inserting them one by one it builds a
+// balanced tree of depth O(log n), so later operations on the heap do not
+// suffer from the degenerate shape sorted input would otherwise produce.
+// The complexity is O(n).
+func FromSorted(sorted []heap.Item) *PairHeap {
+	p := New()
+	if len(sorted) > 0 {
+		p.root = buildSorted(sorted, 0, nil)
+		p.size = len(sorted)
+		p.seq = len(sorted)
+	}
+	return p
+}
+
+// buildSorted builds a tree rooted at sorted[0] with the rest split evenly
+// between two subtrees; seq is the insertion sequence of sorted[0]
+func buildSorted(sorted []heap.Item, seq int, parent *node) *node {
+	n := &node{item: sorted[0], parent: parent, seq: seq}
+	rest := sorted[1:]
+	mid := (len(rest) + 1) / 2
+	if mid > 0 {
+		n.children = append(n.children, buildSorted(rest[:mid], seq+1, n))
+	}
+	if len(rest) > mid {
+		n.children = append(n.children, buildSorted(rest[mid:], seq+1+mid, n))
+	}
+	return n
+}
+
+// FromSliceInPlace returns a PairHeap holding the items of s, which must
+// already be ordered as a binary heap: no item is smaller than its parent at
+// index (i-1)/2. The binary heap tree is reused as is, so the heap is built
+// without comparing any items.
+// The complexity is O(n).
+func FromSliceInPlace(s []heap.Item) *PairHeap {
+	p := New()
+	if len(s) == 0 {
+		return p
+	}
+	nodes := make([]node, len(s))
+	for i := range nodes {
+		nodes[i].item = s[i]
+		nodes[i].seq = i
+		if i > 0 {
+			parent := &nodes[(i-1)/2]
+			nodes[i].parent = parent
+			parent.children = append(parent.children, &nodes[i])
+		}
+	}
+	p.root = &nodes[0]
+	p.size = len(s)
+	p.seq = len(s)
 	return p
 }
 
-// New returns an initialized PairHeap.
-func New() *PairHeap { return new(PairHeap).Init() }
+// compare orders a and b using the heap comparator
+func (p *PairHeap) compare(a, b heap.Item) int {
+	if p.countComparisons {
+		p.metrics.Comparisons++
+	}
+	return p.cmp(a, b)
+}
+
+// compareItems orders items by their own Compare method
+func compareItems(a, b heap.Item) int {
+	return a.Compare(b)
+}
 
 // IsEmpty returns true if PairHeap p is empty.
 // The complexity is O(1).
@@ -82,9 +228,34 @@ func (p *PairHeap) IsEmpty() bool {
 	return p.root.item == nil
 }
 
+// Size returns the number of items in the PairHeap.
+// The complexity is O(1).
+func (p *PairHeap) Size() int {
+	return p.size
+}
+
 // Resets the current PairHeap
 func (p *PairHeap) Clear() {
+	defer p.holdNewMin(nil)()
+	p.invalidateAll()
+	p.hasElements = false
+	p.reset()
+}
+
+// invalidateAll invalidates the handles of the items still in the heap
+func (p *PairHeap) invalidateAll() {
+	if p.hasElements && p.root != nil && !p.IsEmpty() {
+		p.root.visit((*node).invalidate)
+	}
+}
+
+// reset empties the heap, leaving its former nodes and their handles alone
+func (p *PairHeap) reset() {
 	p.root = &node{}
+	p.size = 0
+	if p.max != nil {
+		p.max.reset()
+	}
 }
 
 // Find the smallest item in the priority queue.
@@ -96,14 +267,247 @@ func (p *PairHeap) FindMin() heap.Item {
 	return p.root.item
 }
 
+// Extremum returns the item ranking first by the heap comparator: the
+// minimum for a min-heap and the maximum for a heap built with a reversed
+// comparator. It is the same as FindMin, named for algorithms that do not
+// care about the orientation.
+// The complexity is O(1).
+func (p *PairHeap) Extremum() heap.Item {
+	return p.FindMin()
+}
+
 // Inserts the value to the PairHeap and returns the item
 // The complexity is O(1).
 func (p *PairHeap) Insert(v heap.Item) heap.Item {
-	n := node{item: v}
-	merge(&p.root, &n)
-	return n.item
+	return p.insertItem(v).item
+}
+
+// insertItem inserts v in a new node and returns it
+func (p *PairHeap) insertItem(v heap.Item) *node {
+	if p.clock != nil {
+		defer p.timeOp(&p.timings.Insert, p.clock())
+	}
+	n := &node{item: v, seq: p.seq}
+	p.seq++
+	p.insert(n)
+	p.metrics.Inserts++
+	return n
+}
+
+// InsertAndMinChanged inserts v and returns true if v became the new
+// minimum, i.e. the heap was empty or v compares below the previous minimum.
+// The complexity is O(1).
+func (p *PairHeap) InsertAndMinChanged(v heap.Item) bool {
+	changed := p.IsEmpty() || p.compare(v, p.root.item) < 0
+	p.Insert(v)
+	return changed
+}
+
+// Meld merges all the items of other into p and leaves other empty, ready
+// to be reused on its own. Melding a heap into itself does nothing.
+// Both heaps must use Item.Compare or come from the same NewWithComparator
+// call; Meld panics otherwise.
+// The complexity is O(1), or O(m) for an other of m items when only one of
+// the heaps uses WithMaxTracking or when other allows nodes more children
+// than WithMaxChildren does for p.
+func (p *PairHeap) Meld(other *PairHeap) {
+	if p.cmpID != other.cmpID {
+		panic("pairing: cannot meld heaps with different comparators")
+	}
+	if other == p || other.IsEmpty() {
+		return
+	}
+	defer p.holdNewMin(nil)()
+	switch {
+	case p.max != nil && other.max != nil:
+		p.max.Meld(other.max)
+	case p.max != nil:
+		other.root.visit(p.max.insertTwin)
+	case other.max != nil:
+		// drop the links into the max heap of other before it is cleared
+		other.root.visit(func(n *node) { n.twin = nil })
+	}
+	if p.maxChildren > 0 && (other.maxChildren == 0 || other.maxChildren > p.maxChildren) {
+		other.root.visit(p.limitChildren)
+	}
+	other.root.parent = nil
+	p.merge(&p.root, other.root)
+	p.size += other.size
+	p.hasElements = p.hasElements || other.hasElements
+	p.metrics.Melds++
+	other.reset()
+}
+
+// TournamentMeld melds all the heaps into a single new one, pairing them up
+// round by round like a tournament so that each merge joins trees of
+// similar size instead of hanging every heap off a single root. All the
+// heaps are left empty and must share the same comparator.
+// The complexity is O(K) for K heaps.
+func TournamentMeld(heaps []*PairHeap) *PairHeap {
+	if len(heaps) == 0 {
+		return New()
+	}
+	round := append([]*PairHeap(nil), heaps...)
+	for len(round) > 1 {
+		next := round[:0]
+		for i := 0; i+1 < len(round); i += 2 {
+			round[i].Meld(round[i+1])
+			next = append(next, round[i])
+		}
+		if len(round)%2 == 1 {
+			next = append(next, round[len(round)-1])
+		}
+		round = next
+	}
+	result := heaps[0].NewEmpty()
+	result.Meld(round[0])
+	return result
+}
+
+// AttachSubtree hangs the whole tree of sub into p, consuming sub.
+// It is the same operation as Meld.
+// The complexity is O(1).
+func (p *PairHeap) AttachSubtree(sub *PairHeap) {
+	p.Meld(sub)
 }
 
+// MeldCapped merges other into p like Meld and then evicts the largest
+// items until p holds at most max items.
+// The complexity is O(n) per evicted item, or O(log n) amortized
+// WithMaxTracking.
+func (p *PairHeap) MeldCapped(other *PairHeap, max int) {
+	p.Meld(other)
+	for p.size > max && !p.IsEmpty() {
+		p.remove(p.maxNode())
+		p.metrics.Evictions++
+	}
+}
+
+// RemoveTopLargest removes the k largest items from p and returns them in
+// descending order.
+// The complexity is O(n log k + k log n) amortized.
+func (p *PairHeap) RemoveTopLargest(k int) []heap.Item {
+	if k <= 0 || p.IsEmpty() {
+		return nil
+	}
+	// keep the k largest nodes seen so far, smallest of them on top
+	largest := New()
+	for _, n := range p.root.collect(nil) {
+		if largest.Size() < k {
+			largest.Insert(nodeRef{n, p.compare})
+		} else if p.compare(n.item, largest.FindMin().(nodeRef).item) > 0 {
+			largest.DeleteMin()
+			largest.Insert(nodeRef{n, p.compare})
+		}
+	}
+	items := make([]heap.Item, largest.Size())
+	for i := len(items) - 1; i >= 0; i-- {
+		n := largest.DeleteMin().(nodeRef).node
+		p.remove(n)
+		items[i] = n.item
+	}
+	p.metrics.Evictions += len(items)
+	return items
+}
+
+// MeldKeepSmallest merges other into p like Meld and then keeps only the k
+// smallest items of the union, dropping the rest. A negative k keeps nothing.
+// The complexity is O(k log n) amortized.
+func (p *PairHeap) MeldKeepSmallest(other *PairHeap, k int) {
+	if k < 0 {
+		k = 0
+	}
+	p.Meld(other)
+	if p.size <= k {
+		return
+	}
+	defer p.holdNewMin(nil)()
+	kept := make([]*node, 0, k)
+	for len(kept) < k {
+		n := p.root
+		p.unlink(n)
+		kept = append(kept, n)
+	}
+	p.invalidateAll()
+	p.metrics.Evictions += p.size
+	p.reset()
+	for _, n := range kept {
+		p.insert(n)
+	}
+}
+
+// Partition moves every item of p comparing below pivot into less and the
+// rest into greaterEqual, both using the comparator of p. p is left empty.
+// The complexity is O(n).
+func (p *PairHeap) Partition(pivot heap.Item) (less, greaterEqual *PairHeap) {
+	less, greaterEqual = p.NewEmpty(), p.NewEmpty()
+	less.hasElements, greaterEqual.hasElements = p.hasElements, p.hasElements
+	if p.IsEmpty() {
+		return less, greaterEqual
+	}
+	defer p.holdNewMin(nil)()
+	for _, n := range p.root.collect(nil) {
+		n.parent, n.children, n.twin = nil, nil, nil
+		if p.compare(n.item, pivot) < 0 {
+			less.insert(n)
+		} else {
+			greaterEqual.insert(n)
+		}
+	}
+	p.reset()
+	return less, greaterEqual
+}
+
+// RequeueMin removes the smallest item and passes it to computeNext. If
+// computeNext reports ok the item it returns, typically a later priority of
+// the same task, is inserted back; otherwise the item is dropped.
+// Does nothing on an empty heap.
+// The complexity is O(log n) amortized.
+func (p *PairHeap) RequeueMin(computeNext func(item heap.Item) (next heap.Item, ok bool)) {
+	if p.IsEmpty() {
+		return
+	}
+	defer p.holdNewMin(nil)()
+	// pop without going through deleteItem, the heap only drains if the item
+	// is dropped
+	var start time.Time
+	if p.clock != nil {
+		start = p.clock()
+	}
+	n := p.root
+	p.remove(n)
+	p.metrics.DeleteMins++
+	if p.clock != nil {
+		p.timeOp(&p.timings.DeleteMin, start)
+	}
+	if next, ok := computeNext(n.item); ok {
+		p.Insert(next)
+	} else {
+		p.checkEmpty()
+	}
+}
+
+// Coalesce replaces every run of equally comparing items with the single
+// item obtained by folding them with combine, which must be associative and
+// should return an item comparing equal to its arguments.
+// The complexity is O(n log n).
+func (p *PairHeap) Coalesce(combine func(a, b heap.Item) heap.Item) {
+	defer p.holdNewMin(nil)()
+	var items []heap.Item
+	for !p.IsEmpty() {
+		item := p.root.item
+		p.remove(p.root)
+		if last := len(items) - 1; last >= 0 && p.compare(items[last], item) == 0 {
+			items[last] = combine(items[last], item)
+		} else {
+			items = append(items, item)
+		}
+	}
+	for _, item := range items {
+		p.insert(&node{item: item, seq: p.seq})
+		p.seq++
+	}
+}
 
 // toDelete details what item to remove in a node call.
 type toDelete int
@@ -116,60 +520,279 @@ const (
 // DeleteMin removes the top most value from the PairHeap and returns it
 // The complexity is O(log n) amortized.
 func (p *PairHeap) DeleteMin() heap.Item {
+	if p.clock != nil {
+		defer p.timeOp(&p.timings.DeleteMin, p.clock())
+	}
 	return p.deleteItem(nil, removeMin)
 }
 
 // Deletes a node from the heap and returns the item
 // The complexity is O(log n) amortized.
 func (p *PairHeap) Delete(item heap.Item) heap.Item {
+	if p.clock != nil {
+		defer p.timeOp(&p.timings.Delete, p.clock())
+	}
 	return p.deleteItem(item, removeItem)
 }
 
+// Page removes and returns up to n smallest items in ascending order,
+// reporting whether the PairHeap still has items left afterwards.
+// The complexity is O(n log m) amortized.
+func (p *PairHeap) Page(n int) (items []heap.Item, more bool) {
+	for i := 0; i < n && !p.IsEmpty(); i++ {
+		items = append(items, p.DeleteMin())
+	}
+	return items, !p.IsEmpty()
+}
+
+// Consume returns an iterator yielding the items in ascending order, in the
+// shape of an iter.Seq so it can be ranged over from Go 1.23.
+// Items are removed from p as they are yielded, so p is consumed by a full
+// iteration; stopping early leaves the remaining items in p.
+// The complexity is O(log n) amortized per item.
+func (p *PairHeap) Consume() func(yield func(heap.Item) bool) {
+	return func(yield func(heap.Item) bool) {
+		for !p.IsEmpty() {
+			if !yield(p.DeleteMin()) {
+				return
+			}
+		}
+	}
+}
+
+// DrainToChan removes all the items in ascending order and sends each on ch,
+// blocking whenever ch is full. The caller owns ch and is responsible for
+// closing it.
+// The complexity is O(n log n).
+func (p *PairHeap) DrainToChan(ch chan<- heap.Item) {
+	for !p.IsEmpty() {
+		ch <- p.DeleteMin()
+	}
+}
+
 func (p *PairHeap) deleteItem(item heap.Item, typ toDelete) heap.Item {
-	var result node
+	if p.IsEmpty() {
+		return nil
+	}
+	var n *node
 
 	switch typ {
 	case removeMin:
-		if len(p.root.children) == 0 {
-			result = *p.root
-			p.root.item = nil
-		} else {
-			result = *mergePairs(&p.root, p.root.children)
-		}
+		n = p.root
 	case removeItem:
-		node := p.root.findNode(item)
-		if node == nil {
+		n = p.root.findNode(item, p.compare)
+		if n == nil {
 			return nil
-		} else {
-			children := node.detach()
-			result = *mergePairs(&p.root, append(p.root.children, children...))
 		}
 	default:
 		panic("invalid type")
 	}
-	return result.item
+	p.remove(n)
+	if typ == removeMin {
+		p.metrics.DeleteMins++
+	} else {
+		p.metrics.Deletes++
+	}
+	p.checkEmpty()
+	return n.item
 }
 
 // Adjusts the value to the node item and returns it
 // The complexity is O(n) amortized.
 func (p *PairHeap) Adjust(item heap.Item, new heap.Item) heap.Item {
-	n := p.root.findNode(item)
+	if p.IsEmpty() {
+		return nil
+	}
+	n := p.root.findNode(item, p.compare)
 	if n == nil {
 		return nil
 	}
+	p.replace(n, new)
+	return n.item
+}
 
-	if n == p.root {
-		p.DeleteMin()
-		return p.Insert(new)
-	} else {
-		children := n.detach()
-		n.item = new
-		mergePairs(&p.root, append(p.root.children, append([]*node{n}, children...)...))
-		return n.item
+// replace moves n to the position of its new item
+func (p *PairHeap) replace(n *node, new heap.Item) {
+	defer p.holdNewMin(n)()
+	p.unlink(n)
+	n.item = new
+	p.insert(n)
+}
+
+// MaxDepth returns the number of nodes on the longest path from the root.
+// The complexity is O(n).
+func (p *PairHeap) MaxDepth() int {
+	if p.IsEmpty() {
+		return 0
+	}
+	return p.root.depth()
+}
+
+// LeafCount returns the number of nodes without children.
+// The complexity is O(n).
+func (p *PairHeap) LeafCount() int {
+	if p.IsEmpty() {
+		return 0
+	}
+	return p.root.leaves()
+}
+
+// ChildSubtreeSizes returns the number of nodes of each subtree hanging off
+// the root, in the order of the root children.
+// The complexity is O(n).
+func (p *PairHeap) ChildSubtreeSizes() []int {
+	sizes := []int{}
+	if !p.IsEmpty() {
+		for _, child := range p.root.children {
+			sizes = append(sizes, child.count())
+		}
+	}
+	return sizes
+}
+
+// BalanceFactor returns MaxDepth divided by log2(Size+1). Values close to 1
+// mean a well balanced tree while large values point to a degenerate one.
+// Empty and single item heaps report 0.
+// The complexity is O(n).
+func (p *PairHeap) BalanceFactor() float64 {
+	if p.size <= 1 {
+		return 0
+	}
+	return float64(p.MaxDepth()) / math.Log2(float64(p.size)+1)
+}
+
+// Reshuffle rebuilds the PairHeap by reinserting its items in a random
+// order drawn from rng. The items are unchanged but the tree it produces is
+// shallow with high probability, undoing e.g. the chain left by a sorted
+// insertion order.
+// The complexity is O(n).
+func (p *PairHeap) Reshuffle(rng *rand.Rand) {
+	if p.IsEmpty() {
+		return
+	}
+	defer p.holdNewMin(nil)()
+	nodes := p.root.collect(nil)
+	rng.Shuffle(len(nodes), func(i, j int) {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	})
+	p.reset()
+	for _, n := range nodes {
+		n.parent, n.children = nil, nil
+		p.insert(n)
+	}
+}
+
+// CollapseChains flattens every chain of single child nodes by moving the
+// nodes below its head up to the parent of the head, which reduces the depth
+// without changing the items. Descending insertions, for instance, build one
+// chain holding the whole heap.
+// The complexity is O(n).
+func (p *PairHeap) CollapseChains() {
+	if p.IsEmpty() {
+		return
+	}
+	stack := []*node{p.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		var lifted []*node
+		for _, c := range n.children {
+			for len(c.children) == 1 {
+				next := c.children[0]
+				c.children = nil
+				next.parent = n
+				lifted = append(lifted, next)
+				c = next
+			}
+		}
+		if len(lifted) > 0 {
+			n.children = append(n.children, lifted...)
+			p.limitChildren(n)
+		}
+		stack = append(stack, n.children...)
+	}
+}
+
+// SortedRuns returns all the items in ascending order split into
+// consecutive runs of runSize items; the last run may be shorter.
+// p is left untouched.
+// The complexity is O(n log n).
+func (p *PairHeap) SortedRuns(runSize int) [][]heap.Item {
+	if runSize < 1 {
+		panic("invalid run size")
+	}
+	var runs [][]heap.Item
+	c := p.clone()
+	for !c.IsEmpty() {
+		run, _ := c.Page(runSize)
+		runs = append(runs, run)
 	}
+	return runs
 }
 
-// Do calls function cb on each element of the PairingHeap, in order of appearance.
+// MergeWithSlice returns the sorted union of the items of p and sorted,
+// which must already be in ascending order. p is left untouched.
+// The complexity is O(n log n + m) for m items in sorted.
+func (p *PairHeap) MergeWithSlice(sorted []heap.Item) []heap.Item {
+	merged := make([]heap.Item, 0, p.size+len(sorted))
+	c := p.clone()
+	for !c.IsEmpty() {
+		item := c.root.item
+		for len(sorted) > 0 && p.cmp(sorted[0], item) < 0 {
+			merged = append(merged, sorted[0])
+			sorted = sorted[1:]
+		}
+		merged = append(merged, c.DeleteMin())
+	}
+	return append(merged, sorted...)
+}
+
+// ListNode is an element of the singly linked list built by ToSortedList.
+type ListNode struct {
+	Item heap.Item
+	Next *ListNode
+}
+
+// ToSortedList returns the items as a linked list in ascending order,
+// or nil for an empty heap. p is left untouched.
+// The complexity is O(n log n).
+func (p *PairHeap) ToSortedList() *ListNode {
+	var head ListNode
+	tail := &head
+	c := p.clone()
+	for !c.IsEmpty() {
+		tail.Next = &ListNode{Item: c.DeleteMin()}
+		tail = tail.Next
+	}
+	return head.Next
+}
+
+// PriorityBucket groups the items sharing a priority.
+type PriorityBucket struct {
+	// The first item of the group in heap order
+	Priority heap.Item
+	Items    []heap.Item
+}
+
+// ByPriority returns the items grouped into buckets of equally comparing
+// items, in ascending order of priority. p is left untouched.
+// The complexity is O(n log n).
+func (p *PairHeap) ByPriority() []PriorityBucket {
+	var buckets []PriorityBucket
+	c := p.clone()
+	for !c.IsEmpty() {
+		item := c.DeleteMin()
+		if last := len(buckets) - 1; last >= 0 && p.cmp(buckets[last].Priority, item) == 0 {
+			buckets[last].Items = append(buckets[last].Items, item)
+		} else {
+			buckets = append(buckets, PriorityBucket{Priority: item, Items: []heap.Item{item}})
+		}
+	}
+	return buckets
+}
+
+// Do calls function cb on each element of the PairingHeap, in order of
+// appearance in the tree, which is not sorted; see DoSorted.
 // The behavior of Do is undefined if cb changes *p.
 func (p *PairHeap) Do(cb func(item heap.Item)) {
 	if p.IsEmpty() {
@@ -181,13 +804,66 @@ func (p *PairHeap) Do(cb func(item heap.Item)) {
 	visitChildren(p.root.children, cb)
 }
 
+// DoSorted calls function cb on each element of the PairingHeap in ascending
+// order, equally comparing items included. p is left untouched.
+// The complexity is O(n log n).
+func (p *PairHeap) DoSorted(cb func(item heap.Item)) {
+	c := p.clone()
+	for !c.IsEmpty() {
+		cb(c.DeleteMin())
+	}
+}
+
+// RangeDo calls function cb in ascending order on each item comparing within
+// [low, high]. A nil bound leaves that side of the range open.
+// The complexity is O(n + k log n) amortized for k visited items.
+func (p *PairHeap) RangeDo(low, high heap.Item, cb func(item heap.Item)) {
+	c := p.clone()
+	for !c.IsEmpty() {
+		item := c.DeleteMin()
+		if high != nil && p.cmp(item, high) > 0 {
+			return
+		}
+		if low == nil || p.cmp(item, low) >= 0 {
+			cb(item)
+		}
+	}
+}
+
+// StructurallyEqual returns true if p and other have the same tree shape
+// with items comparing equal node for node, siblings in the same order.
+// The complexity is O(n).
+func (p *PairHeap) StructurallyEqual(other *PairHeap) bool {
+	if p.IsEmpty() || other.IsEmpty() {
+		return p.IsEmpty() == other.IsEmpty()
+	}
+	return p.size == other.size && p.root.equal(other.root, p.compare)
+}
+
+// TopNInto fills buf with the len(buf) smallest items in ascending order and
+// returns how many it found, clearing the rest of buf. The heap is not
+// modified and nothing is allocated, so the same buffer can be refreshed
+// repeatedly, e.g. to render the next tasks of a queue.
+// The complexity is O(n * len(buf)) in the worst case, but subtrees that
+// cannot hold any of the smallest items are skipped.
+func (p *PairHeap) TopNInto(buf []heap.Item) int {
+	count := 0
+	if !p.IsEmpty() && len(buf) > 0 {
+		count = p.root.topN(buf, 0, p.compare)
+	}
+	for i := count; i < len(buf); i++ {
+		buf[i] = nil
+	}
+	return count
+}
+
 // Exhausting search of the element that matches item and returns it
 // The complexity is O(n) amortized.
 func (p *PairHeap) Find(item heap.Item) heap.Item {
 	if p.IsEmpty() {
 		return nil
 	}
-	node := p.root.findNode(item)
+	node := p.root.findNode(item, p.compare)
 	if node == nil {
 		return nil
 	} else {
@@ -195,6 +871,275 @@ func (p *PairHeap) Find(item heap.Item) heap.Item {
 	}
 }
 
+// FindByIdentity returns the item stored in the heap that is == to item,
+// telling apart distinct items that merely compare equal, e.g. pointers to
+// different tasks with the same priority. Items must be comparable values.
+// The complexity is O(n).
+func (p *PairHeap) FindByIdentity(item heap.Item) heap.Item {
+	if p.IsEmpty() {
+		return nil
+	}
+	if n := p.root.findIdentical(item, p.compare); n != nil {
+		return n.item
+	}
+	return nil
+}
+
+// Successor returns the smallest item comparing strictly greater than item,
+// or nil if there is none.
+// The complexity is O(n).
+func (p *PairHeap) Successor(item heap.Item) heap.Item {
+	if p.IsEmpty() {
+		return nil
+	}
+	return p.root.successor(item, nil, p.compare)
+}
+
+// Predecessor returns the largest item comparing strictly less than item,
+// or nil if there is none.
+// The complexity is O(n).
+func (p *PairHeap) Predecessor(item heap.Item) heap.Item {
+	if p.IsEmpty() {
+		return nil
+	}
+	return p.root.predecessor(item, nil, p.compare)
+}
+
+// Rank returns the number of items comparing strictly less than item,
+// i.e. the position item would take in sorted order.
+// The complexity is O(n).
+func (p *PairHeap) Rank(item heap.Item) int {
+	if p.IsEmpty() {
+		return 0
+	}
+	return p.root.rank(item, p.compare)
+}
+
+// SampleWeighted returns a random item drawn with probability proportional
+// to weight(item), using a single weighted reservoir pass over the heap.
+// Items with a non positive weight are never drawn; nil is returned if no
+// item has a positive weight. The heap is not modified.
+// The complexity is O(n).
+func (p *PairHeap) SampleWeighted(rng *rand.Rand, weight func(item heap.Item) float64) heap.Item {
+	var chosen heap.Item
+	total := 0.0
+	p.Do(func(item heap.Item) {
+		w := weight(item)
+		if w <= 0 {
+			return
+		}
+		total += w
+		if rng.Float64()*total < w {
+			chosen = item
+		}
+	})
+	return chosen
+}
+
+// InversionCount returns the number of item pairs whose insertion order
+// disagrees with their priority order. Items melded in from another heap keep
+// the sequence numbers they were given there.
+// The complexity is O(n log n).
+func (p *PairHeap) InversionCount() int {
+	if p.IsEmpty() {
+		return 0
+	}
+	nodes := p.root.collect(nil)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		cmp := p.compare(nodes[i].item, nodes[j].item)
+		if cmp == 0 {
+			return nodes[i].seq < nodes[j].seq
+		}
+		return cmp < 0
+	})
+	seqs := make([]int, len(nodes))
+	for i, n := range nodes {
+		seqs[i] = n.seq
+	}
+	return countInversions(seqs, make([]int, len(seqs)))
+}
+
+// countInversions merge sorts s using buf and returns the pairs found out of order
+func countInversions(s, buf []int) int {
+	if len(s) < 2 {
+		return 0
+	}
+	mid := len(s) / 2
+	count := countInversions(s[:mid], buf[:mid]) + countInversions(s[mid:], buf[mid:])
+	i, j, k := 0, mid, 0
+	for i < mid && j < len(s) {
+		if s[j] < s[i] {
+			count += mid - i
+			buf[k] = s[j]
+			j++
+		} else {
+			buf[k] = s[i]
+			i++
+		}
+		k++
+	}
+	k += copy(buf[k:], s[i:mid])
+	copy(buf[k:], s[j:])
+	copy(s, buf[:len(s)])
+	return count
+}
+
+// nodeRef is an item referring to a node, ordered by the node item
+type nodeRef struct {
+	*node
+	cmp func(a, b heap.Item) int
+}
+
+func (r nodeRef) Compare(than heap.Item) int {
+	return r.cmp(r.item, than.(nodeRef).item)
+}
+
+// depth returns the number of nodes on the longest path down from n
+func (n *node) depth() int {
+	max := 0
+	for _, child := range n.children {
+		if d := child.depth(); d > max {
+			max = d
+		}
+	}
+	return max + 1
+}
+
+// findIdentical returns the node under n holding exactly item
+func (n *node) findIdentical(item heap.Item, cmp func(a, b heap.Item) int) *node {
+	if n.item == item {
+		return n
+	}
+	if cmp(n.item, item) > 0 {
+		return nil // the whole subtree is greater than item
+	}
+	for _, child := range n.children {
+		if found := child.findIdentical(item, cmp); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// successor returns the smallest item under n greater than item, or best if
+// there is none smaller than best
+func (n *node) successor(item, best heap.Item, cmp func(a, b heap.Item) int) heap.Item {
+	if cmp(n.item, item) > 0 {
+		// the whole subtree is at least n.item
+		if best == nil || cmp(n.item, best) < 0 {
+			return n.item
+		}
+		return best
+	}
+	for _, child := range n.children {
+		best = child.successor(item, best, cmp)
+	}
+	return best
+}
+
+// predecessor returns the largest item under n less than item, or best if
+// there is none larger than best
+func (n *node) predecessor(item, best heap.Item, cmp func(a, b heap.Item) int) heap.Item {
+	if cmp(n.item, item) >= 0 {
+		return best // the whole subtree is at least item
+	}
+	if best == nil || cmp(n.item, best) > 0 {
+		best = n.item
+	}
+	for _, child := range n.children {
+		best = child.predecessor(item, best, cmp)
+	}
+	return best
+}
+
+// count returns the number of nodes under n, n included
+func (n *node) count() int {
+	count := 1
+	for _, child := range n.children {
+		count += child.count()
+	}
+	return count
+}
+
+// leaves returns the number of childless nodes under n
+func (n *node) leaves() int {
+	if len(n.children) == 0 {
+		return 1
+	}
+	count := 0
+	for _, child := range n.children {
+		count += child.leaves()
+	}
+	return count
+}
+
+// equal returns true if the trees under n and m have the same shape and items
+func (n *node) equal(m *node, cmp func(a, b heap.Item) int) bool {
+	if len(n.children) != len(m.children) || cmp(n.item, m.item) != 0 {
+		return false
+	}
+	for i, child := range n.children {
+		if !child.equal(m.children[i], cmp) {
+			return false
+		}
+	}
+	return true
+}
+
+// rank returns the number of items under n less than item
+func (n *node) rank(item heap.Item, cmp func(a, b heap.Item) int) int {
+	if cmp(n.item, item) >= 0 {
+		return 0 // the whole subtree is at least item
+	}
+	count := 1
+	for _, child := range n.children {
+		count += child.rank(item, cmp)
+	}
+	return count
+}
+
+// topN merges the items under n into the sorted buf holding count items
+// and returns the new count
+func (n *node) topN(buf []heap.Item, count int, cmp func(a, b heap.Item) int) int {
+	if count == len(buf) && cmp(n.item, buf[count-1]) >= 0 {
+		return count // the whole subtree is at least the largest kept item
+	}
+	i := count
+	if count == len(buf) {
+		i-- // drop the largest kept item
+	} else {
+		count++
+	}
+	for ; i > 0 && cmp(buf[i-1], n.item) > 0; i-- {
+		buf[i] = buf[i-1]
+	}
+	buf[i] = n.item
+	for _, child := range n.children {
+		count = child.topN(buf, count, cmp)
+	}
+	return count
+}
+
+// findMax returns the node holding the largest item under n
+func (n *node) findMax(cmp func(a, b heap.Item) int) *node {
+	max := n
+	for _, child := range n.children {
+		if m := child.findMax(cmp); cmp(m.item, max.item) > 0 {
+			max = m
+		}
+	}
+	return max
+}
+
+// collect appends n and all of its descendants to nodes
+func (n *node) collect(nodes []*node) []*node {
+	nodes = append(nodes, n)
+	for _, child := range n.children {
+		nodes = child.collect(nodes)
+	}
+	return nodes
+}
+
 func visitChildren(children []*node, cb func(item heap.Item)) {
 	if len(children) == 0 {
 		return
@@ -205,51 +1150,176 @@ func visitChildren(children []*node, cb func(item heap.Item)) {
 	}
 }
 
-func merge(first **node, second *node) *node {
+func (p *PairHeap) merge(first **node, second *node) *node {
 	q := *first
 	if q.item == nil { // Case when root is empty
 		*first = second
 		return *first
 	}
 
-	cmp := q.item.Compare(second.item)
+	cmp := p.compare(q.item, second.item)
 	if cmp < 0 {
 		// put 'second' as the first child of 'first' and update the parent
 		q.children = append([]*node{second}, q.children...)
 		second.parent = *first
+		p.limitChildren(q)
 		return *first
 	} else {
 		// put 'first' as the first child of 'second' and update the parent
 		second.children = append([]*node{q}, second.children...)
 		q.parent = second
+		p.limitChildren(second)
 		*first = second
 		return second
 	}
 }
 
-// Merges heaps together
-func mergePairs(root **node, heaps []*node) *node {
-	q := *root
-	if len(heaps) == 1 {
-		*root = heaps[0]
-		heaps[0].parent = nil
-		return q
+// limitChildren pairs up the oldest children of n until it has no more than
+// maxChildren of them
+func (p *PairHeap) limitChildren(n *node) {
+	for p.maxChildren > 0 && len(n.children) > p.maxChildren {
+		last := len(n.children) - 1
+		a, b := n.children[last-1], n.children[last]
+		n.children = n.children[:last-1]
+		a.parent, b.parent = nil, nil
+		paired := p.merge(&a, b)
+		paired.parent = n
+		n.children = append(n.children, paired)
 	}
-	var merged *node
-	for { // iteratively merge heaps
-		if len(heaps) == 0 {
-			break
+}
+
+// clone returns a deep copy of the PairHeap structure sharing its items.
+// Hooks are not copied, so working on the clone stays invisible to callers.
+func (p *PairHeap) clone() *PairHeap {
+	c := *p
+	c.root = p.root.clone(nil)
+	c.onDegenerate = nil
+	c.onEmpty = nil
+	c.onNewMin = nil
+	c.trackMax, c.max = false, nil
+	c.spill = nil
+	c.clock = nil
+	c.hasElements = false
+	return &c
+}
+
+// clone copies n and its descendants, attaching the copy to parent
+func (n *node) clone(parent *node) *node {
+	c := &node{item: n.item, parent: parent, seq: n.seq}
+	if len(n.children) > 0 {
+		c.children = make([]*node, len(n.children))
+		for i, child := range n.children {
+			c.children[i] = child.clone(c)
 		}
-		if merged == nil {
-			merged = merge(&heaps[0], heaps[1])
-			heaps = heaps[2:]
-		} else {
-			merged = merge(&merged, heaps[0])
-			heaps = heaps[1:]
+	}
+	return c
+}
+
+// insert merges the single node n into the heap
+func (p *PairHeap) insert(n *node) {
+	old := p.root
+	p.merge(&p.root, n)
+	if p.onNewMin != nil {
+		p.newMin(old, old.item, nil)
+	}
+	p.size++
+	if p.max != nil {
+		p.max.insertTwin(n)
+	}
+	p.checkDegenerate()
+}
+
+// remove takes n out of the heap for good, invalidating its handle
+func (p *PairHeap) remove(n *node) {
+	p.unlink(n)
+	n.invalidate()
+}
+
+// unlink takes n out of the heap and merges its children back in
+func (p *PairHeap) unlink(n *node) {
+	old := p.root
+	children := n.children
+	n.children = nil
+	if n == p.root {
+		p.root = &node{}
+	} else {
+		n.detach()
+	}
+	if len(children) > 0 {
+		p.merge(&p.root, p.mergePairs(children))
+	}
+	p.size--
+	if p.max != nil {
+		p.max.remove(n.twin)
+		n.twin = nil
+	}
+	if p.onNewMin != nil {
+		p.newMin(old, old.item, nil)
+	}
+	p.checkDegenerate()
+}
+
+// newMin calls the OnNewMin hook if the root is no longer old, which held
+// oldItem, or if it is touched, whose item has been replaced, unless the
+// new root compares equal to oldItem
+func (p *PairHeap) newMin(old *node, oldItem heap.Item, touched *node) {
+	if p.root == old && p.root != touched || oldItem == nil && p.IsEmpty() {
+		return
+	}
+	if oldItem != nil && !p.IsEmpty() && p.cmp(oldItem, p.root.item) == 0 {
+		return
+	}
+	p.onNewMin(oldItem, p.root.item)
+}
+
+// holdNewMin silences the OnNewMin hook through an operation moving several
+// nodes. The returned function restores it, calling it at most once for the
+// whole operation; touched is the node whose item the operation replaces.
+func (p *PairHeap) holdNewMin(touched *node) func() {
+	cb, old, oldItem := p.onNewMin, p.root, p.root.item
+	p.onNewMin = nil
+	return func() {
+		if p.onNewMin = cb; cb != nil {
+			p.newMin(old, oldItem, touched)
 		}
 	}
-	*root = merged
-	merged.parent = nil
+}
+
+// checkDegenerate calls the OnDegenerate hook if the heap has grown too deep.
+// Measuring the depth is O(n) so it only runs once every max(size, 64)
+// operations, keeping the amortized cost O(1).
+func (p *PairHeap) checkDegenerate() {
+	if p.onDegenerate == nil {
+		return
+	}
+	if p.untilCheck--; p.untilCheck > 0 {
+		return
+	}
+	p.untilCheck = p.size
+	if p.untilCheck < degenerateInterval {
+		p.untilCheck = degenerateInterval
+	}
+	depth := p.MaxDepth()
+	if float64(depth) > degenerateFactor*math.Log2(float64(p.size)+1) {
+		p.onDegenerate(depth, p.size)
+	}
+}
 
-	return q
+// checkEmpty calls the OnEmpty hook if a delete has just emptied the heap
+func (p *PairHeap) checkEmpty() {
+	if p.onEmpty != nil && p.IsEmpty() {
+		p.onEmpty()
+	}
+}
+
+// Merges heaps together and returns the new root
+func (p *PairHeap) mergePairs(heaps []*node) *node {
+	for _, n := range heaps {
+		n.parent = nil
+	}
+	merged := heaps[0]
+	for _, n := range heaps[1:] { // iteratively merge heaps
+		merged = p.merge(&merged, n)
+	}
+	return merged
 }
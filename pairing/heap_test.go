@@ -1,6 +1,7 @@
 package pairing
 
 import (
+	"math/rand"
 	"testing"
 	"github.com/stretchr/testify/suite"
 	"github.com/stretchr/testify/assert"
@@ -118,6 +119,997 @@ func (suite *PairingHeapTestSuite) TestDelete() {
 	assert.NotNil(suite.T(), suite.heap.Find(Int(5)))
 	assert.NotNil(suite.T(), suite.heap.Find(Int(3)))
 	assert.NotNil(suite.T(), suite.heap.Find(Int(9)))
+	assert.NotNil(suite.T(), suite.heap.Find(Int(2)))
+	assert.Equal(suite.T(), 5, suite.heap.Size())
+}
+
+func (suite *PairingHeapTestSuite) TestSize() {
+	rng := rand.New(rand.NewSource(42))
+	for _, n := range []int{0, 1, 2, 10, 100, 500} {
+		h := New()
+		values := make([]int, n)
+		for i := range values {
+			values[i] = rng.Intn(50)
+			h.Insert(Int(values[i]))
+		}
+		assert.Equal(suite.T(), n, h.Size())
+
+		deleted := 0
+		for _, v := range values {
+			switch rng.Intn(4) {
+			case 0:
+				if h.Delete(Int(v)) != nil {
+					deleted++
+				}
+			case 1:
+				if h.DeleteMin() != nil {
+					deleted++
+				}
+			case 2:
+				h.Adjust(Int(v), Int(rng.Intn(50)))
+			}
+		}
+		// missing items are neither deleted nor adjusted
+		assert.Nil(suite.T(), h.Delete(Int(100)))
+		assert.Nil(suite.T(), h.Adjust(Int(100), Int(1)))
+
+		count := 0
+		h.Do(func(go_heaps.Item) { count++ })
+		assert.Equal(suite.T(), n-deleted, count)
+		assert.Equal(suite.T(), count, h.Size())
+	}
+}
+
+func (suite *PairingHeapTestSuite) TestSizeSingleItem() {
+	suite.heap.Insert(Int(1))
+	assert.Equal(suite.T(), Int(1), suite.heap.DeleteMin())
+	assert.Equal(suite.T(), 0, suite.heap.Size())
+	assert.Nil(suite.T(), suite.heap.DeleteMin())
+	assert.Equal(suite.T(), 0, suite.heap.Size())
+
+	// adjusting the root replaces it without changing the size
+	suite.heap.Insert(Int(1))
+	suite.heap.Adjust(Int(1), Int(2))
+	assert.Equal(suite.T(), 1, suite.heap.Size())
+	suite.heap.Clear()
+	assert.Equal(suite.T(), 0, suite.heap.Size())
+}
+
+func (suite *PairingHeapTestSuite) TestMeldSize() {
+	cases := []struct {
+		a, b int
+	}{
+		{0, 0},
+		{0, 3},
+		{3, 0},
+		{1, 1},
+		{4, 7},
+		{10, 2},
+	}
+	for _, c := range cases {
+		a, b := New(), New()
+		for i := 0; i < c.a; i++ {
+			a.Insert(Int(i * 2))
+		}
+		for i := 0; i < c.b; i++ {
+			b.Insert(Int(i*2 + 1))
+		}
+
+		a.Meld(b)
+		assert.Equal(suite.T(), c.a+c.b, a.Size())
+		assert.Equal(suite.T(), 0, b.Size())
+		assert.True(suite.T(), b.IsEmpty())
+		assert.Equal(suite.T(), c.a+c.b == 0, a.IsEmpty())
+
+		for i := 0; i < c.a+c.b; i++ {
+			a.DeleteMin()
+		}
+		assert.Equal(suite.T(), 0, a.Size())
+		assert.True(suite.T(), a.IsEmpty())
+	}
+}
+
+func (suite *PairingHeapTestSuite) TestWrap() {
+	type task struct {
+		name     string
+		priority int
+	}
+	byPriority := func(a, b task) bool { return a.priority > b.priority }
+
+	suite.heap.Insert(go_heaps.Wrap(task{"low", 1}, byPriority))
+	suite.heap.Insert(go_heaps.Wrap(task{"high", 9}, byPriority))
+	suite.heap.Insert(go_heaps.Wrap(task{"mid", 5}, byPriority))
+
+	var names []string
+	for !suite.heap.IsEmpty() {
+		names = append(names, go_heaps.Unwrap[task](suite.heap.DeleteMin()).name)
+	}
+	assert.Equal(suite.T(), []string{"high", "mid", "low"}, names)
+}
+
+func (suite *PairingHeapTestSuite) TestPage() {
+	for _, v := range []int{7, 3, 9, 1, 5, 8, 2} {
+		suite.heap.Insert(Int(v))
+	}
+
+	items, more := suite.heap.Page(3)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(1), Int(2), Int(3)}, items)
+	assert.True(suite.T(), more)
+
+	items, more = suite.heap.Page(3)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(5), Int(7), Int(8)}, items)
+	assert.True(suite.T(), more)
+
+	items, more = suite.heap.Page(3)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(9)}, items)
+	assert.False(suite.T(), more)
+
+	items, more = suite.heap.Page(3)
+	assert.Empty(suite.T(), items)
+	assert.False(suite.T(), more)
+}
+
+func (suite *PairingHeapTestSuite) TestConsume() {
+	for _, v := range []int{6, 2, 8, 4, 10} {
+		suite.heap.Insert(Int(v))
+	}
+
+	var got []go_heaps.Item
+	suite.heap.Consume()(func(item go_heaps.Item) bool {
+		got = append(got, item)
+		return len(got) < 3
+	})
+	assert.Equal(suite.T(), []go_heaps.Item{Int(2), Int(4), Int(6)}, got)
+	assert.Equal(suite.T(), 2, suite.heap.Size())
+	assert.Equal(suite.T(), Int(8), suite.heap.FindMin())
+
+	got = got[:0]
+	suite.heap.Consume()(func(item go_heaps.Item) bool {
+		got = append(got, item)
+		return true
+	})
+	assert.Equal(suite.T(), []go_heaps.Item{Int(8), Int(10)}, got)
+	assert.True(suite.T(), suite.heap.IsEmpty())
+}
+
+func (suite *PairingHeapTestSuite) TestInversionCount() {
+	assert.Equal(suite.T(), 0, suite.heap.InversionCount())
+
+	// (3,1) (3,2) (4,2) (5,2) are out of order
+	for _, v := range []int{3, 1, 4, 5, 2} {
+		suite.heap.Insert(Int(v))
+	}
+	assert.Equal(suite.T(), 4, suite.heap.InversionCount())
+
+	sorted := New()
+	for _, v := range []int{1, 2, 2, 3} {
+		sorted.Insert(Int(v))
+	}
+	assert.Equal(suite.T(), 0, sorted.InversionCount())
+}
+
+func (suite *PairingHeapTestSuite) TestMeldCapped() {
+	other := New()
+	for _, v := range []int{9, 1, 7, 3} {
+		suite.heap.Insert(Int(v))
+	}
+	for _, v := range []int{2, 8, 4} {
+		other.Insert(Int(v))
+	}
+
+	suite.heap.MeldCapped(other, 4)
+	assert.True(suite.T(), other.IsEmpty())
+	assert.Equal(suite.T(), 4, suite.heap.Size())
+
+	items, more := suite.heap.Page(5)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(1), Int(2), Int(3), Int(4)}, items)
+	assert.False(suite.T(), more)
+}
+
+func (suite *PairingHeapTestSuite) TestSortedRuns() {
+	assert.Empty(suite.T(), suite.heap.SortedRuns(3))
+
+	values := []int{12, 4, 9, 1, 15, 7, 3, 10}
+	for _, v := range values {
+		suite.heap.Insert(Int(v))
+	}
+
+	runs := suite.heap.SortedRuns(3)
+	assert.Len(suite.T(), runs, 3)
+	assert.Len(suite.T(), runs[2], 2)
+
+	var all []go_heaps.Item
+	for _, run := range runs {
+		for i := 1; i < len(run); i++ {
+			assert.True(suite.T(), run[i-1].Compare(run[i]) <= 0)
+		}
+		all = append(all, run...)
+	}
+	assert.Equal(suite.T(), []go_heaps.Item{Int(1), Int(3), Int(4), Int(7), Int(9), Int(10), Int(12), Int(15)}, all)
+	assert.Equal(suite.T(), len(values), suite.heap.Size())
+	assert.Equal(suite.T(), Int(1), suite.heap.FindMin())
+}
+
+func (suite *PairingHeapTestSuite) TestWithMaxChildren() {
+	h := New(WithMaxChildren(3))
+	for i := 0; i < 100; i++ {
+		h.Insert(Int(i))
+	}
+	assert.True(suite.T(), maxFanOut(h.root) <= 3)
+
+	h.Delete(Int(50))
+	h.Adjust(Int(70), Int(5))
+	assert.True(suite.T(), maxFanOut(h.root) <= 3)
+	assert.Equal(suite.T(), 99, h.Size())
+
+	prev := h.DeleteMin()
+	for !h.IsEmpty() {
+		next := h.DeleteMin()
+		assert.True(suite.T(), prev.Compare(next) <= 0)
+		assert.True(suite.T(), maxFanOut(h.root) <= 3)
+		prev = next
+	}
+
+	// melding an unbounded heap applies the bound to its nodes too
+	h = New(WithMaxChildren(2))
+	h.Insert(Int(-1))
+	wide := New()
+	for i := 0; i <= 10; i++ {
+		wide.Insert(Int(i))
+	}
+	assert.Equal(suite.T(), 10, maxFanOut(wide.root))
+	h.Meld(wide)
+	assert.True(suite.T(), maxFanOut(h.root) <= 2)
+	assert.Equal(suite.T(), 12, h.Size())
+	items, _ := h.Page(12)
+	for i := 1; i < len(items); i++ {
+		assert.True(suite.T(), items[i-1].Compare(items[i]) <= 0)
+	}
+}
+
+// maxFanOut returns the largest number of children of any node under n
+func maxFanOut(n *node) int {
+	max := len(n.children)
+	for _, child := range n.children {
+		if m := maxFanOut(child); m > max {
+			max = m
+		}
+	}
+	return max
+}
+
+func (suite *PairingHeapTestSuite) TestMaxDepth() {
+	assert.Equal(suite.T(), 0, suite.heap.MaxDepth())
+	suite.heap.Insert(Int(3))
+	assert.Equal(suite.T(), 1, suite.heap.MaxDepth())
+	suite.heap.Insert(Int(4))
+	suite.heap.Insert(Int(5))
+	assert.Equal(suite.T(), 2, suite.heap.MaxDepth())
+	suite.heap.Insert(Int(2))
+	assert.Equal(suite.T(), 3, suite.heap.MaxDepth())
+}
+
+func (suite *PairingHeapTestSuite) TestOnDegenerate() {
+	fired := 0
+	h := New(OnDegenerate(func(depth, size int) {
+		fired++
+		assert.True(suite.T(), depth > size/2)
+	}))
+	for i := 1000; i > 0; i-- {
+		h.Insert(Int(i))
+	}
+	assert.True(suite.T(), fired > 0)
+
+	fired = 0
+	h = New(OnDegenerate(func(depth, size int) { fired++ }))
+	for i := 0; i < 1000; i++ {
+		h.Insert(Int(i))
+	}
+	assert.Equal(suite.T(), 0, fired)
+}
+
+func (suite *PairingHeapTestSuite) TestSuccessor() {
+	assert.Nil(suite.T(), suite.heap.Successor(Int(1)))
+	for _, v := range []int{10, 20, 30, 40, 50} {
+		suite.heap.Insert(Int(v))
+	}
+
+	assert.Equal(suite.T(), Int(40), suite.heap.Successor(Int(30)))
+	assert.Equal(suite.T(), Int(30), suite.heap.Successor(Int(25)))
+	assert.Equal(suite.T(), Int(10), suite.heap.Successor(Int(0)))
+	assert.Nil(suite.T(), suite.heap.Successor(Int(50)))
+}
+
+func (suite *PairingHeapTestSuite) TestPredecessor() {
+	assert.Nil(suite.T(), suite.heap.Predecessor(Int(1)))
+	for _, v := range []int{35, 5, 50, 20, 15, 40} {
+		suite.heap.Insert(Int(v))
+	}
+	suite.heap.DeleteMin()
+
+	assert.Equal(suite.T(), Int(20), suite.heap.Predecessor(Int(35)))
+	assert.Equal(suite.T(), Int(35), suite.heap.Predecessor(Int(38)))
+	assert.Equal(suite.T(), Int(50), suite.heap.Predecessor(Int(100)))
+	assert.Nil(suite.T(), suite.heap.Predecessor(Int(15)))
+}
+
+func (suite *PairingHeapTestSuite) TestRemoveTopLargest() {
+	assert.Empty(suite.T(), suite.heap.RemoveTopLargest(3))
+	for _, v := range []int{6, 2, 9, 4, 10, 1, 7, 3, 8, 5} {
+		suite.heap.Insert(Int(v))
+	}
+
+	removed := suite.heap.RemoveTopLargest(3)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(10), Int(9), Int(8)}, removed)
+	assert.Equal(suite.T(), 7, suite.heap.Size())
+
+	rest, _ := suite.heap.Page(10)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(1), Int(2), Int(3), Int(4), Int(5), Int(6), Int(7)}, rest)
+}
+
+func (suite *PairingHeapTestSuite) TestNewWithComparator() {
+	max := NewWithComparator(reverse)
+	for _, v := range []int{3, 9, 1, 7} {
+		max.Insert(Int(v))
+	}
+	assert.Equal(suite.T(), Int(9), max.FindMin())
+
+	items, _ := max.Page(4)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(9), Int(7), Int(3), Int(1)}, items)
+}
+
+func (suite *PairingHeapTestSuite) TestMeldComparatorMismatch() {
+	max := NewWithComparator(reverse)
+	max.Insert(Int(5))
+	suite.heap.Insert(Int(3))
+
+	assert.Panics(suite.T(), func() { suite.heap.Meld(max) })
+	assert.Panics(suite.T(), func() { max.Meld(suite.heap) })
+	assert.Equal(suite.T(), 1, suite.heap.Size())
+	assert.Equal(suite.T(), 1, max.Size())
+
+	other := max.NewEmpty()
+	other.Insert(Int(8))
+	max.Meld(other)
+	assert.Equal(suite.T(), Int(8), max.FindMin())
+	assert.NotPanics(suite.T(), func() { suite.heap.Meld(New()) })
+
+	// closures made by the same func share their code but may order apart
+	orderBy := func(descending bool) func(a, b go_heaps.Item) int {
+		return func(a, b go_heaps.Item) int {
+			if descending {
+				return b.Compare(a)
+			}
+			return a.Compare(b)
+		}
+	}
+	asc, desc := NewWithComparator(orderBy(false)), NewWithComparator(orderBy(true))
+	asc.Insert(Int(1))
+	desc.Insert(Int(3))
+	assert.Panics(suite.T(), func() { asc.Meld(desc) })
+	assert.Panics(suite.T(), func() { max.Meld(NewWithComparator(reverse)) })
+}
+
+func (suite *PairingHeapTestSuite) TestFromSorted() {
+	assert.True(suite.T(), FromSorted(nil).IsEmpty())
+
+	sorted := make([]go_heaps.Item, 1000)
+	for i := range sorted {
+		sorted[i] = Int(i)
+	}
+	h := FromSorted(sorted)
+	assert.Equal(suite.T(), 1000, h.Size())
+	assert.Equal(suite.T(), 0, h.InversionCount())
+	// a perfectly balanced binary tree of 1000 nodes is 10 levels deep
+	assert.True(suite.T(), h.MaxDepth() <= 11)
+
+	h.Insert(Int(-1))
+	items, more := h.Page(1001)
+	assert.Equal(suite.T(), append([]go_heaps.Item{Int(-1)}, sorted...), items)
+	assert.False(suite.T(), more)
+}
+
+func (suite *PairingHeapTestSuite) TestAttachSubtree() {
+	sub := New()
+	for _, v := range []int{5, 1, 8} {
+		sub.Insert(Int(v))
+	}
+	for _, v := range []int{4, 9, 2} {
+		suite.heap.Insert(Int(v))
+	}
+
+	suite.heap.AttachSubtree(sub)
+	assert.True(suite.T(), sub.IsEmpty())
+	assert.Equal(suite.T(), 6, suite.heap.Size())
+
+	items, _ := suite.heap.Page(6)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(1), Int(2), Int(4), Int(5), Int(8), Int(9)}, items)
+}
+
+func (suite *PairingHeapTestSuite) TestRangeDo() {
+	for _, v := range []int{8, 3, 12, 5, 1, 9, 5} {
+		suite.heap.Insert(Int(v))
+	}
+	collect := func(low, high go_heaps.Item) []go_heaps.Item {
+		var items []go_heaps.Item
+		suite.heap.RangeDo(low, high, func(item go_heaps.Item) {
+			items = append(items, item)
+		})
+		return items
+	}
+
+	assert.Equal(suite.T(), []go_heaps.Item{Int(3), Int(5), Int(5), Int(8)}, collect(Int(3), Int(8)))
+	assert.Equal(suite.T(), []go_heaps.Item{Int(9), Int(12)}, collect(Int(9), nil))
+	assert.Equal(suite.T(), []go_heaps.Item{Int(1), Int(3)}, collect(nil, Int(4)))
+	assert.Empty(suite.T(), collect(Int(6), Int(7)))
+	assert.Equal(suite.T(), 7, suite.heap.Size())
+}
+
+func (suite *PairingHeapTestSuite) TestBalanceFactor() {
+	assert.Equal(suite.T(), 0.0, suite.heap.BalanceFactor())
+	suite.heap.Insert(Int(1))
+	assert.Equal(suite.T(), 0.0, suite.heap.BalanceFactor())
+
+	sorted, random := New(), New()
+	for i := 1023; i >= 0; i-- {
+		sorted.Insert(Int(i))
+	}
+	for _, v := range rand.New(rand.NewSource(3)).Perm(1024) {
+		random.Insert(Int(v))
+	}
+	assert.True(suite.T(), sorted.BalanceFactor() > 100)
+	assert.True(suite.T(), random.BalanceFactor() < 3)
+}
+
+func (suite *PairingHeapTestSuite) TestReshuffle() {
+	for i := 999; i >= 0; i-- {
+		suite.heap.Insert(Int(i))
+	}
+	assert.Equal(suite.T(), 1000, suite.heap.MaxDepth())
+
+	suite.heap.Reshuffle(rand.New(rand.NewSource(5)))
+	assert.True(suite.T(), suite.heap.MaxDepth() < 50)
+	assert.Equal(suite.T(), 1000, suite.heap.Size())
+	for i := 0; i < 1000; i++ {
+		assert.Equal(suite.T(), Int(i), suite.heap.DeleteMin())
+	}
+}
+
+func (suite *PairingHeapTestSuite) TestMeldKeepSmallest() {
+	other := New()
+	for _, v := range []int{14, 3, 22, 8, 5} {
+		suite.heap.Insert(Int(v))
+	}
+	for _, v := range []int{7, 1, 30, 4} {
+		other.Insert(Int(v))
+	}
+
+	suite.heap.MeldKeepSmallest(other, 5)
+	assert.True(suite.T(), other.IsEmpty())
+	assert.Equal(suite.T(), 5, suite.heap.Size())
+	items, _ := suite.heap.Page(10)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(1), Int(3), Int(4), Int(5), Int(7)}, items)
+
+	small := New()
+	small.Insert(Int(2))
+	suite.heap.MeldKeepSmallest(small, 5)
+	assert.Equal(suite.T(), 1, suite.heap.Size())
+
+	suite.heap.MeldKeepSmallest(New(), -1)
+	assert.True(suite.T(), suite.heap.IsEmpty())
+}
+
+func (suite *PairingHeapTestSuite) TestPartition() {
+	for _, v := range []int{12, 5, 9, 1, 15, 9, 3, 20} {
+		suite.heap.Insert(Int(v))
+	}
+
+	less, greaterEqual := suite.heap.Partition(Int(9))
+	assert.True(suite.T(), suite.heap.IsEmpty())
+	assert.Equal(suite.T(), 3, less.Size())
+	assert.Equal(suite.T(), 5, greaterEqual.Size())
+
+	low, _ := less.Page(10)
+	high, _ := greaterEqual.Page(10)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(1), Int(3), Int(5)}, low)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(9), Int(9), Int(12), Int(15), Int(20)}, high)
+
+	less, greaterEqual = New().Partition(Int(0))
+	assert.True(suite.T(), less.IsEmpty())
+	assert.True(suite.T(), greaterEqual.IsEmpty())
+}
+
+func (suite *PairingHeapTestSuite) TestLeafCount() {
+	assert.Equal(suite.T(), 0, suite.heap.LeafCount())
+	suite.heap.Insert(Int(5))
+	assert.Equal(suite.T(), 1, suite.heap.LeafCount())
+
+	// 1 ends up on top of 3 and 5, with 6 and 7 hanging off 5
+	suite.heap.Insert(Int(6))
+	suite.heap.Insert(Int(7))
+	suite.heap.Insert(Int(1))
+	suite.heap.Insert(Int(3))
+	assert.Equal(suite.T(), 3, suite.heap.LeafCount())
+}
+
+func (suite *PairingHeapTestSuite) TestStructurallyEqual() {
+	build := func(values ...int) *PairHeap {
+		h := New()
+		for _, v := range values {
+			h.Insert(Int(v))
+		}
+		return h
+	}
+	assert.True(suite.T(), New().StructurallyEqual(New()))
+	assert.False(suite.T(), New().StructurallyEqual(build(1)))
+
+	a, b := build(4, 2, 7, 5), build(4, 2, 7, 5)
+	assert.True(suite.T(), a.StructurallyEqual(b))
+
+	// same items, different insertion order and thus different shape
+	c := build(2, 4, 5, 7)
+	assert.False(suite.T(), a.StructurallyEqual(c))
+
+	a.DeleteMin()
+	b.DeleteMin()
+	assert.True(suite.T(), a.StructurallyEqual(b))
+}
+
+// retry is a task ordered by the delay before its next attempt
+type retry struct {
+	name     string
+	delay    int
+	attempts int
+}
+
+func (r retry) Compare(than go_heaps.Item) int {
+	return r.delay - than.(retry).delay
+}
+
+func (suite *PairingHeapTestSuite) TestRequeueMin() {
+	backoff := func(item go_heaps.Item) (go_heaps.Item, bool) {
+		r := item.(retry)
+		r.delay *= 2
+		r.attempts++
+		return r, r.attempts < 3
+	}
+	suite.heap.RequeueMin(backoff)
+	assert.True(suite.T(), suite.heap.IsEmpty())
+
+	suite.heap.Insert(retry{name: "a", delay: 1})
+	suite.heap.Insert(retry{name: "b", delay: 3})
+
+	var order []string
+	for !suite.heap.IsEmpty() {
+		order = append(order, suite.heap.FindMin().(retry).name)
+		suite.heap.RequeueMin(backoff)
+	}
+	// a: 1 2 4, b: 3 6 12
+	assert.Equal(suite.T(), []string{"a", "a", "b", "a", "b", "b"}, order)
+}
+
+func (suite *PairingHeapTestSuite) TestByPriority() {
+	assert.Empty(suite.T(), suite.heap.ByPriority())
+
+	tasks := []retry{{"c", 2, 0}, {"a", 1, 0}, {"d", 3, 0}, {"b", 1, 0}, {"e", 2, 0}, {"f", 2, 0}}
+	for _, r := range tasks {
+		suite.heap.Insert(r)
+	}
+
+	buckets := suite.heap.ByPriority()
+	assert.Len(suite.T(), buckets, 3)
+	var names [][]string
+	for i, b := range buckets {
+		assert.Equal(suite.T(), i+1, b.Priority.(retry).delay)
+		var group []string
+		for _, item := range b.Items {
+			group = append(group, item.(retry).name)
+		}
+		names = append(names, group)
+	}
+	assert.ElementsMatch(suite.T(), []string{"a", "b"}, names[0])
+	assert.ElementsMatch(suite.T(), []string{"c", "e", "f"}, names[1])
+	assert.Equal(suite.T(), []string{"d"}, names[2])
+	assert.Equal(suite.T(), len(tasks), suite.heap.Size())
+}
+
+// job is a pointer item compared by priority only
+type job struct {
+	id       string
+	priority int
+}
+
+func (j *job) Compare(than go_heaps.Item) int {
+	return j.priority - than.(*job).priority
+}
+
+func (suite *PairingHeapTestSuite) TestFindByIdentity() {
+	first, second := &job{"first", 2}, &job{"second", 2}
+	suite.heap.Insert(&job{"other", 1})
+	suite.heap.Insert(first)
+	suite.heap.Insert(second)
+	suite.heap.Insert(&job{"last", 3})
+
+	assert.True(suite.T(), suite.heap.FindByIdentity(second) == second)
+	assert.True(suite.T(), suite.heap.FindByIdentity(first) == first)
+	assert.Nil(suite.T(), suite.heap.FindByIdentity(&job{"second", 2}))
+	assert.Nil(suite.T(), New().FindByIdentity(first))
+}
+
+func (suite *PairingHeapTestSuite) TestToSortedList() {
+	assert.Nil(suite.T(), suite.heap.ToSortedList())
+
+	for _, v := range []int{6, 1, 9, 3, 3, 7} {
+		suite.heap.Insert(Int(v))
+	}
+	var items []go_heaps.Item
+	for l := suite.heap.ToSortedList(); l != nil; l = l.Next {
+		items = append(items, l.Item)
+	}
+	assert.Equal(suite.T(), []go_heaps.Item{Int(1), Int(3), Int(3), Int(6), Int(7), Int(9)}, items)
+	assert.Equal(suite.T(), 6, suite.heap.Size())
+}
+
+func (suite *PairingHeapTestSuite) TestRank() {
+	assert.Equal(suite.T(), 0, suite.heap.Rank(Int(5)))
+	for _, v := range []int{10, 40, 20, 20, 30, 50} {
+		suite.heap.Insert(Int(v))
+	}
+
+	assert.Equal(suite.T(), 0, suite.heap.Rank(Int(5)))
+	assert.Equal(suite.T(), 0, suite.heap.Rank(Int(10)))
+	assert.Equal(suite.T(), 1, suite.heap.Rank(Int(20)))
+	assert.Equal(suite.T(), 3, suite.heap.Rank(Int(25)))
+	assert.Equal(suite.T(), 5, suite.heap.Rank(Int(50)))
+	assert.Equal(suite.T(), 6, suite.heap.Rank(Int(99)))
+}
+
+func (suite *PairingHeapTestSuite) TestSampleWeighted() {
+	rng := rand.New(rand.NewSource(11))
+	inverse := func(item go_heaps.Item) float64 {
+		return 1 / float64(item.(go_heaps.Integer))
+	}
+	assert.Nil(suite.T(), suite.heap.SampleWeighted(rng, inverse))
+
+	for _, v := range []int{1, 2, 3} {
+		suite.heap.Insert(Int(v))
+	}
+	const draws = 30000
+	counts := map[go_heaps.Item]int{}
+	for i := 0; i < draws; i++ {
+		counts[suite.heap.SampleWeighted(rng, inverse)]++
+	}
+	// weights 1, 1/2 and 1/3 give probabilities 6/11, 3/11 and 2/11
+	assert.InDelta(suite.T(), 6.0/11, float64(counts[Int(1)])/draws, 0.02)
+	assert.InDelta(suite.T(), 3.0/11, float64(counts[Int(2)])/draws, 0.02)
+	assert.InDelta(suite.T(), 2.0/11, float64(counts[Int(3)])/draws, 0.02)
+	assert.Equal(suite.T(), 3, suite.heap.Size())
+}
+
+// order is an amount keyed by price
+type order struct {
+	price, amount int
+}
+
+func (o order) Compare(than go_heaps.Item) int {
+	return o.price - than.(order).price
+}
+
+func (suite *PairingHeapTestSuite) TestCoalesce() {
+	sum := func(a, b go_heaps.Item) go_heaps.Item {
+		return order{a.(order).price, a.(order).amount + b.(order).amount}
+	}
+	suite.heap.Coalesce(sum)
+	assert.True(suite.T(), suite.heap.IsEmpty())
+
+	for _, o := range []order{{10, 1}, {12, 5}, {10, 2}, {11, 4}, {12, 1}, {10, 3}} {
+		suite.heap.Insert(o)
+	}
+	suite.heap.Coalesce(sum)
+	assert.Equal(suite.T(), 3, suite.heap.Size())
+	items, _ := suite.heap.Page(3)
+	assert.Equal(suite.T(), []go_heaps.Item{order{10, 6}, order{11, 4}, order{12, 6}}, items)
+}
+
+func (suite *PairingHeapTestSuite) TestDrainToChan() {
+	for _, v := range []int{5, 2, 8, 1} {
+		suite.heap.Insert(Int(v))
+	}
+
+	ch := make(chan go_heaps.Item, 4)
+	suite.heap.DrainToChan(ch)
+	close(ch)
+	assert.True(suite.T(), suite.heap.IsEmpty())
+
+	var items []go_heaps.Item
+	for item := range ch {
+		items = append(items, item)
+	}
+	assert.Equal(suite.T(), []go_heaps.Item{Int(1), Int(2), Int(5), Int(8)}, items)
+}
+
+func (suite *PairingHeapTestSuite) TestTopNInto() {
+	buf := make([]go_heaps.Item, 3)
+	assert.Equal(suite.T(), 0, suite.heap.TopNInto(buf))
+
+	suite.heap.Insert(Int(9))
+	suite.heap.Insert(Int(4))
+	assert.Equal(suite.T(), 2, suite.heap.TopNInto(buf))
+	assert.Equal(suite.T(), []go_heaps.Item{Int(4), Int(9), nil}, buf)
+
+	for _, v := range rand.New(rand.NewSource(2)).Perm(50) {
+		suite.heap.Insert(Int(v + 10))
+	}
+	suite.heap.Insert(Int(6))
+	assert.Equal(suite.T(), 3, suite.heap.TopNInto(buf))
+	assert.Equal(suite.T(), []go_heaps.Item{Int(4), Int(6), Int(9)}, buf)
+
+	suite.heap.DeleteMin()
+	suite.heap.Delete(Int(9))
+	allocs := testing.AllocsPerRun(10, func() {
+		suite.heap.TopNInto(buf)
+	})
+	assert.Equal(suite.T(), 0.0, allocs)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(6), Int(10), Int(11)}, buf)
+	assert.Equal(suite.T(), 51, suite.heap.Size())
+}
+
+func (suite *PairingHeapTestSuite) TestInsertAndMinChanged() {
+	assert.True(suite.T(), suite.heap.InsertAndMinChanged(Int(5)))
+	assert.False(suite.T(), suite.heap.InsertAndMinChanged(Int(8)))
+	assert.False(suite.T(), suite.heap.InsertAndMinChanged(Int(5)))
+	assert.True(suite.T(), suite.heap.InsertAndMinChanged(Int(2)))
+	assert.Equal(suite.T(), Int(2), suite.heap.FindMin())
+	assert.Equal(suite.T(), 4, suite.heap.Size())
+}
+
+func (suite *PairingHeapTestSuite) TestChildSubtreeSizes() {
+	assert.Empty(suite.T(), suite.heap.ChildSubtreeSizes())
+	suite.heap.Insert(Int(5))
+	assert.Empty(suite.T(), suite.heap.ChildSubtreeSizes())
+
+	// 5 gets 6 and 7 as children, then 1 takes 5 as its only child
+	// and 4 and 3 become the newer children of 1
+	for _, v := range []int{6, 7, 1, 4, 3} {
+		suite.heap.Insert(Int(v))
+	}
+	assert.Equal(suite.T(), []int{1, 1, 3}, suite.heap.ChildSubtreeSizes())
+}
+
+func (suite *PairingHeapTestSuite) TestCollapseChains() {
+	suite.heap.CollapseChains()
+	for i := 99; i >= 0; i-- {
+		suite.heap.Insert(Int(i))
+	}
+	suite.heap.Insert(Int(50))
+	suite.heap.Insert(Int(60))
+	assert.Equal(suite.T(), 100, suite.heap.MaxDepth())
+
+	suite.heap.CollapseChains()
+	assert.Equal(suite.T(), 2, suite.heap.MaxDepth())
+	assert.Equal(suite.T(), 102, suite.heap.Size())
+	assert.Equal(suite.T(), 101, suite.heap.LeafCount())
+
+	items, _ := suite.heap.Page(102)
+	for i := 1; i < len(items); i++ {
+		assert.True(suite.T(), items[i-1].Compare(items[i]) <= 0)
+	}
+	assert.Len(suite.T(), items, 102)
+}
+
+func (suite *PairingHeapTestSuite) TestExtremum() {
+	max := NewWithComparator(reverse)
+	assert.Nil(suite.T(), suite.heap.Extremum())
+	assert.Nil(suite.T(), max.Extremum())
+
+	for _, v := range []int{4, 11, 2, 8} {
+		suite.heap.Insert(Int(v))
+		max.Insert(Int(v))
+	}
+	assert.Equal(suite.T(), Int(2), suite.heap.Extremum())
+	assert.Equal(suite.T(), suite.heap.FindMin(), suite.heap.Extremum())
+	assert.Equal(suite.T(), Int(11), max.Extremum())
+}
+
+func (suite *PairingHeapTestSuite) TestMergeWithSlice() {
+	assert.Empty(suite.T(), suite.heap.MergeWithSlice(nil))
+	sorted := []go_heaps.Item{Int(2), Int(3), Int(10), Int(12)}
+	assert.Equal(suite.T(), sorted, suite.heap.MergeWithSlice(sorted))
+
+	for _, v := range []int{7, 1, 3, 15} {
+		suite.heap.Insert(Int(v))
+	}
+	assert.Equal(suite.T(),
+		[]go_heaps.Item{Int(1), Int(2), Int(3), Int(3), Int(7), Int(10), Int(12), Int(15)},
+		suite.heap.MergeWithSlice(sorted))
+	assert.Equal(suite.T(), 4, suite.heap.Size())
+	assert.Equal(suite.T(), Int(1), suite.heap.FindMin())
+}
+
+func (suite *PairingHeapTestSuite) TestMeld() {
+	other := New()
+	for _, v := range []int{6, 2, 9} {
+		suite.heap.Insert(Int(v))
+	}
+	for _, v := range []int{5, 1, 8} {
+		other.Insert(Int(v))
+	}
+
+	suite.heap.Meld(other)
+	assert.Equal(suite.T(), Int(1), suite.heap.FindMin())
+	assert.True(suite.T(), other.IsEmpty())
+	assert.Nil(suite.T(), other.FindMin())
+
+	// nodes coming from other can still be detached from their new parents
+	assert.Equal(suite.T(), Int(8), suite.heap.Delete(Int(8)))
+	assert.Equal(suite.T(), Int(4), suite.heap.Adjust(Int(5), Int(4)))
+
+	// other is independent from p after the meld
+	other.Insert(Int(0))
+	assert.Equal(suite.T(), Int(1), suite.heap.FindMin())
+	assert.Equal(suite.T(), 1, other.Size())
+
+	// melding into an empty heap adopts the other root
+	empty := New()
+	empty.Meld(other)
+	assert.Equal(suite.T(), Int(0), empty.FindMin())
+	assert.True(suite.T(), other.IsEmpty())
+
+	suite.heap.Meld(suite.heap)
+	assert.Equal(suite.T(), 5, suite.heap.Size())
+	items, _ := suite.heap.Page(5)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(1), Int(2), Int(4), Int(6), Int(9)}, items)
+}
+
+func (suite *PairingHeapTestSuite) TestMeldMaxTracking() {
+	tracked := New(WithMaxTracking())
+	tracked.Insert(Int(3))
+	tracked.Insert(Int(7))
+
+	suite.heap.Insert(Int(5))
+	suite.heap.Meld(tracked)
+	assert.True(suite.T(), tracked.IsEmpty())
+	assert.True(suite.T(), tracked.max.IsEmpty())
+	suite.heap.Do(func(item go_heaps.Item) {
+		assert.Nil(suite.T(), suite.heap.root.findNode(item, suite.heap.cmp).twin)
+	})
+	assert.Equal(suite.T(), Int(7), suite.heap.DeleteMax())
+}
+
+func (suite *PairingHeapTestSuite) TestFromSliceInPlace() {
+	assert.True(suite.T(), FromSliceInPlace(nil).IsEmpty())
+
+	// a valid binary min-heap layout
+	s := []go_heaps.Item{Int(1), Int(3), Int(2), Int(7), Int(4), Int(5), Int(9), Int(8)}
+	h := FromSliceInPlace(s)
+	assert.Equal(suite.T(), 8, h.Size())
+	assert.Equal(suite.T(), 4, h.MaxDepth())
+
+	items, more := h.Page(8)
+	assert.Equal(suite.T(), []go_heaps.Item{Int(1), Int(2), Int(3), Int(4), Int(5), Int(7), Int(8), Int(9)}, items)
+	assert.False(suite.T(), more)
+}
+
+func (suite *PairingHeapTestSuite) TestElement() {
+	e := suite.heap.InsertElement(Int(5))
+	suite.heap.Insert(Int(3))
+	other := New()
+	seven := other.InsertElement(Int(7))
+	other.Insert(Int(1))
+	suite.heap.Meld(other)
+	assert.Equal(suite.T(), Int(7), seven.Item())
+
+	assert.Equal(suite.T(), Int(2), suite.heap.AdjustElement(e, Int(2)))
+	assert.Equal(suite.T(), Int(2), e.Item())
+	assert.Equal(suite.T(), Int(9), suite.heap.AdjustElement(seven, Int(9)))
+	assert.Equal(suite.T(), Int(9), suite.heap.DeleteElement(seven))
+	assert.Nil(suite.T(), seven.Item())
+	assert.Nil(suite.T(), suite.heap.DeleteElement(seven))
+	assert.Nil(suite.T(), suite.heap.AdjustElement(seven, Int(4)))
+	assert.Equal(suite.T(), 3, suite.heap.Size())
+
+	assert.Equal(suite.T(), Int(1), suite.heap.DeleteMin())
+	// e now holds the root; adjusting it upwards keeps the order
+	assert.Equal(suite.T(), Int(6), suite.heap.AdjustElement(e, Int(6)))
+	assert.Equal(suite.T(), Int(3), suite.heap.DeleteMin())
+	assert.Equal(suite.T(), Int(6), e.Item())
+	assert.Equal(suite.T(), Int(6), suite.heap.DeleteMin())
+	assert.Nil(suite.T(), e.Item())
+
+	e = suite.heap.InsertElement(Int(8))
+	suite.heap.Clear()
+	assert.Nil(suite.T(), e.Item())
+	assert.Equal(suite.T(), 0, suite.heap.Size())
+
+	// kept items keep their handles, dropped ones lose them
+	e = suite.heap.InsertElement(Int(1))
+	dropped := suite.heap.InsertElement(Int(6))
+	suite.heap.Insert(Int(5))
+	suite.heap.MeldKeepSmallest(New(), 1)
+	assert.Equal(suite.T(), Int(1), e.Item())
+	assert.Nil(suite.T(), dropped.Item())
+	suite.heap.Clear()
+	assert.Nil(suite.T(), e.Item())
+
+	e = suite.heap.InsertElement(Int(2))
+	suite.heap.Init()
+	assert.Nil(suite.T(), e.Item())
+	suite.heap.Insert(Int(9))
+	assert.Nil(suite.T(), suite.heap.DeleteElement(e))
+	assert.Equal(suite.T(), 1, suite.heap.Size())
+}
+
+func (suite *PairingHeapTestSuite) TestOnEmpty() {
+	calls := 0
+	h := New(OnEmpty(func() { calls++ }))
+	h.Insert(Int(1))
+	h.Insert(Int(2))
+	h.DeleteMin()
+	assert.Equal(suite.T(), 0, calls)
+	h.Delete(Int(2))
+	assert.Equal(suite.T(), 1, calls)
+	h.DeleteMin()
+	h.Delete(Int(2))
+	assert.Equal(suite.T(), 1, calls)
+
+	e := h.InsertElement(Int(3))
+	h.DeleteElement(e)
+	assert.Equal(suite.T(), 2, calls)
+
+	// requeueing the last item keeps the heap busy, dropping it drains it
+	h.Insert(Int(4))
+	h.RequeueMin(func(item go_heaps.Item) (go_heaps.Item, bool) { return Int(5), true })
+	assert.Equal(suite.T(), 2, calls)
+	h.RequeueMin(func(item go_heaps.Item) (go_heaps.Item, bool) { return nil, false })
+	assert.Equal(suite.T(), 3, calls)
+}
+
+func (suite *PairingHeapTestSuite) TestDoSorted() {
+	calls := 0
+	suite.heap.DoSorted(func(item go_heaps.Item) { calls++ })
+	assert.Equal(suite.T(), 0, calls)
+
+	for _, i := range []int{5, 2, 8, 2, 9, 1, 5} {
+		suite.heap.Insert(Int(i))
+	}
+	suite.heap.DeleteMin()
+	var first, second []go_heaps.Item
+	suite.heap.DoSorted(func(item go_heaps.Item) { first = append(first, item) })
+	suite.heap.DoSorted(func(item go_heaps.Item) { second = append(second, item) })
+	assert.Equal(suite.T(), []go_heaps.Item{Int(2), Int(2), Int(5), Int(5), Int(8), Int(9)}, first)
+	assert.Equal(suite.T(), first, second)
+	assert.Equal(suite.T(), 6, suite.heap.Size())
+	assert.Equal(suite.T(), Int(2), suite.heap.FindMin())
+}
+
+func (suite *PairingHeapTestSuite) TestOnNewMin() {
+	var changes [][2]go_heaps.Item
+	h := New(OnNewMin(func(old, new go_heaps.Item) {
+		changes = append(changes, [2]go_heaps.Item{old, new})
+	}))
+	h.Insert(Int(5))
+	h.Insert(Int(7))
+	h.Insert(Int(3))
+	h.Insert(Int(3)) // ties leave the minimum as it is
+	h.DeleteMin()
+	h.Adjust(Int(3), Int(4))
+	h.Adjust(Int(7), Int(8))
+	h.DeleteMin()
+	h.Delete(Int(8))
+	h.RequeueMin(func(item go_heaps.Item) (go_heaps.Item, bool) { return Int(6), true })
+	h.DeleteMin()
+	assert.Equal(suite.T(), [][2]go_heaps.Item{
+		{nil, Int(5)},
+		{Int(5), Int(3)},
+		{Int(3), Int(4)},
+		{Int(4), Int(5)},
+		{Int(5), Int(6)},
+		{Int(6), nil},
+	}, changes)
+}
+
+func reverse(a, b go_heaps.Item) int {
+	return b.Compare(a)
 }
 
 func Int(value int) go_heaps.Integer {
@@ -0,0 +1,54 @@
+package pairing
+
+import (
+	heap "github.com/theodesp/go-heaps"
+)
+
+// FindMax returns the largest item in the PairHeap.
+// The complexity is O(1) WithMaxTracking and O(n) otherwise.
+func (p *PairHeap) FindMax() heap.Item {
+	if p.IsEmpty() {
+		return nil
+	}
+	return p.maxNode().item
+}
+
+// DeleteMax removes the largest item from the PairHeap and returns it.
+// The complexity is O(log n) amortized WithMaxTracking and O(n) otherwise.
+func (p *PairHeap) DeleteMax() heap.Item {
+	if p.IsEmpty() {
+		return nil
+	}
+	n := p.maxNode()
+	p.remove(n)
+	p.metrics.Deletes++
+	p.checkEmpty()
+	return n.item
+}
+
+// maxNode returns the node holding the largest item of a non empty heap
+func (p *PairHeap) maxNode() *node {
+	if p.max != nil {
+		return p.max.root.twin
+	}
+	return p.root.findMax(p.compare)
+}
+
+// reverse orders items the opposite way of the heap comparator
+func (p *PairHeap) reverse(a, b heap.Item) int {
+	return p.cmp(b, a)
+}
+
+// insertTwin adds a node mirroring n to the max-ordered heap p
+func (p *PairHeap) insertTwin(n *node) {
+	n.twin = &node{item: n.item, twin: n}
+	p.insert(n.twin)
+}
+
+// visit calls cb on n and all of its descendants
+func (n *node) visit(cb func(n *node)) {
+	cb(n)
+	for _, child := range n.children {
+		child.visit(cb)
+	}
+}
@@ -0,0 +1,67 @@
+package pairing
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theodesp/go-heaps"
+)
+
+func TestFindMax(t *testing.T) {
+	for _, p := range []*PairHeap{New(), New(WithMaxTracking())} {
+		assert.Nil(t, p.FindMax())
+		assert.Nil(t, p.DeleteMax())
+		for _, v := range []int{4, 9, 2, 7} {
+			p.Insert(Int(v))
+		}
+		assert.Equal(t, Int(9), p.FindMax())
+		assert.Equal(t, Int(9), p.DeleteMax())
+		assert.Equal(t, Int(7), p.FindMax())
+		assert.Equal(t, 3, p.Size())
+		assert.Equal(t, 1, p.Metrics().Deletes)
+	}
+}
+
+func TestWithMaxTracking(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	p := New(WithMaxTracking())
+	var values []int
+	for i := 0; i < 100; i++ {
+		v := rng.Intn(1000)
+		values = append(values, v)
+		p.Insert(Int(v))
+	}
+
+	// both ends, a deletion and an adjustment in the middle
+	p.Delete(Int(values[10]))
+	p.Adjust(Int(values[20]), Int(-1))
+	values[20] = -1
+	values = append(values[:10], values[11:]...)
+
+	// melding tracked and untracked heaps keeps both orders in sync
+	tracked, untracked := New(WithMaxTracking()), New()
+	tracked.Insert(Int(2000))
+	untracked.Insert(Int(3000))
+	untracked.Insert(Int(-5))
+	p.Meld(tracked)
+	p.Meld(untracked)
+	values = append(values, 2000, 3000, -5)
+	sort.Ints(values)
+
+	lo, hi := 0, len(values)-1
+	for i := 0; !p.IsEmpty(); i++ {
+		assert.Equal(t, hi-lo+1, p.Size())
+		assert.Equal(t, p.Size(), p.max.Size())
+		if i%2 == 0 {
+			assert.Equal(t, go_heaps.Integer(values[lo]), p.DeleteMin())
+			lo++
+		} else {
+			assert.Equal(t, go_heaps.Integer(values[hi]), p.DeleteMax())
+			hi--
+		}
+	}
+	assert.True(t, p.max.IsEmpty())
+	assert.Nil(t, p.FindMax())
+}
@@ -0,0 +1,64 @@
+package pairing
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theodesp/go-heaps"
+)
+
+// smallHeaps returns k heaps of size items each, all items distinct
+func smallHeaps(k, size int) []*PairHeap {
+	values := rand.New(rand.NewSource(4)).Perm(k * size)
+	heaps := make([]*PairHeap, k)
+	for i := range heaps {
+		heaps[i] = New()
+		for _, v := range values[i*size : (i+1)*size] {
+			heaps[i].Insert(Int(v))
+		}
+	}
+	return heaps
+}
+
+func TestTournamentMeld(t *testing.T) {
+	assert.True(t, TournamentMeld(nil).IsEmpty())
+
+	heaps := smallHeaps(7, 5)
+	p := TournamentMeld(heaps)
+	for _, h := range heaps {
+		assert.True(t, h.IsEmpty())
+	}
+	assert.Equal(t, 35, p.Size())
+	for i := 0; i < 35; i++ {
+		assert.Equal(t, go_heaps.Integer(i), p.DeleteMin())
+	}
+	assert.True(t, p.IsEmpty())
+}
+
+func BenchmarkTournamentMeld(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		heaps := smallHeaps(1000, 4)
+		b.StartTimer()
+		p := TournamentMeld(heaps)
+		for !p.IsEmpty() {
+			p.DeleteMin()
+		}
+	}
+}
+
+func BenchmarkMeldFold(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		heaps := smallHeaps(1000, 4)
+		b.StartTimer()
+		p := New()
+		for _, h := range heaps {
+			p.Meld(h)
+		}
+		for !p.IsEmpty() {
+			p.DeleteMin()
+		}
+	}
+}
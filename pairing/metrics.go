@@ -0,0 +1,48 @@
+package pairing
+
+import (
+	"time"
+)
+
+// Metrics holds cumulative operation counters of a PairHeap
+// along with its current shape.
+type Metrics struct {
+	Inserts    int // successful Insert calls
+	Deletes    int // successful Delete, DeleteMax and DeleteElement calls
+	DeleteMins int // successful DeleteMin calls
+	Melds      int // Meld calls that moved items
+	// items dropped by MeldCapped, MeldKeepSmallest, RemoveTopLargest and Spill
+	Evictions int
+	// item comparisons, only counted by heaps created WithMetrics
+	Comparisons int
+
+	Size     int
+	MaxDepth int
+}
+
+// Metrics returns the counters accumulated since p was created.
+// The complexity is O(n) as the current MaxDepth is measured.
+func (p *PairHeap) Metrics() Metrics {
+	m := p.metrics
+	m.Size = p.Size()
+	m.MaxDepth = p.MaxDepth()
+	return m
+}
+
+// OpTimings holds the total time spent in each timed operation.
+type OpTimings struct {
+	Insert    time.Duration
+	DeleteMin time.Duration
+	Delete    time.Duration
+}
+
+// Timings returns the time accumulated by the operations of a heap created
+// WithClock. It is always zero otherwise.
+func (p *PairHeap) Timings() OpTimings {
+	return p.timings
+}
+
+// timeOp adds the time elapsed since start to total
+func (p *PairHeap) timeOp(total *time.Duration, start time.Time) {
+	*total += p.clock().Sub(start)
+}
@@ -0,0 +1,77 @@
+package pairing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	p := New(WithMetrics())
+	assert.Equal(t, Metrics{}, p.Metrics())
+
+	p.Insert(Int(4)) // empty heap, no comparison
+	p.Insert(Int(2)) // 2 < 4
+	p.Insert(Int(6)) // 2 < 6
+	p.DeleteMin()    // pairs 6 and 4
+	p.Delete(Int(6)) // searches 4 then 6
+	p.Delete(Int(7)) // not found, searches 4
+	other := New(WithMetrics())
+	other.Insert(Int(1))
+	p.Meld(other) // 1 < 4
+	p.Meld(New(WithMetrics()))
+
+	assert.Equal(t, Metrics{
+		Inserts:     3,
+		Deletes:     1,
+		DeleteMins:  1,
+		Melds:       1,
+		Comparisons: 7,
+		Size:        2,
+		MaxDepth:    2,
+	}, p.Metrics())
+
+	// bulk removals add up with the inserts to the size
+	bulk := New()
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		bulk.Insert(Int(v))
+	}
+	bulk.RemoveTopLargest(2)
+	bulk.MeldCapped(New(), 2)
+	bulk.MeldKeepSmallest(New(), 1)
+	m := bulk.Metrics()
+	assert.Equal(t, 4, m.Evictions)
+	assert.Equal(t, m.Inserts-m.Deletes-m.DeleteMins-m.Evictions, m.Size)
+
+	plain := New()
+	plain.Insert(Int(1))
+	plain.Insert(Int(2))
+	assert.Equal(t, 0, plain.Metrics().Comparisons)
+	assert.Equal(t, 2, plain.Metrics().Inserts)
+}
+
+func TestTimings(t *testing.T) {
+	// every reading of the fake clock advances it by a millisecond
+	now := time.Unix(0, 0)
+	clock := func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+
+	p := New(WithClock(clock))
+	p.Insert(Int(3))
+	p.Insert(Int(1))
+	p.Insert(Int(2))
+	p.DeleteMin()
+	p.Delete(Int(3))
+	p.Delete(Int(4))
+	p.Page(5)
+
+	assert.Equal(t, OpTimings{
+		Insert:    3 * time.Millisecond,
+		DeleteMin: 2 * time.Millisecond,
+		Delete:    2 * time.Millisecond,
+	}, p.Timings())
+	assert.Equal(t, OpTimings{}, New().Timings())
+}
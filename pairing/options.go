@@ -0,0 +1,95 @@
+package pairing
+
+import (
+	"io"
+	"time"
+
+	heap "github.com/theodesp/go-heaps"
+)
+
+// Option configures a PairHeap created with New.
+type Option func(p *PairHeap)
+
+// WithMaxChildren bounds the number of children of any node to k.
+// Whenever a merge would give a node more than k children, its two oldest
+// children are paired together, which keeps the cost of scanning a node's
+// children in Delete and Adjust bounded. The extra pairings make Insert and
+// Meld O(log n) amortized instead of O(1), while DeleteMin keeps its
+// O(log n) amortized bound.
+func WithMaxChildren(k int) Option {
+	return func(p *PairHeap) {
+		p.maxChildren = k
+	}
+}
+
+const (
+	degenerateFactor   = 4  // depth allowed per log2(size) before OnDegenerate fires
+	degenerateInterval = 64 // minimum operations between degeneration checks
+)
+
+// OnDegenerate registers a hook called with the current depth and size
+// whenever the heap becomes deeper than a multiple of log2(size), which is
+// typical of adversarial input such as a descending insertion order.
+// The depth is only sampled every max(size, 64) operations.
+func OnDegenerate(cb func(depth, size int)) Option {
+	return func(p *PairHeap) {
+		p.onDegenerate = cb
+	}
+}
+
+// OnEmpty registers a hook called whenever DeleteMin, Delete, DeleteMax or
+// DeleteElement removes the last item, e.g. to stop the workers draining the
+// heap. Deleting from an already empty heap does not call it.
+func OnEmpty(cb func()) Option {
+	return func(p *PairHeap) {
+		p.onEmpty = cb
+	}
+}
+
+// OnNewMin registers a hook called with the previous and the new smallest
+// item whenever the root of the heap changes to an item comparing
+// differently, e.g. to reschedule a timer on the front of the queue. old is
+// nil when the heap was empty and new is nil when it has become empty.
+// Operations moving many items, such as Meld, Adjust or RequeueMin, call it
+// at most once.
+func OnNewMin(cb func(old, new heap.Item)) Option {
+	return func(p *PairHeap) {
+		p.onNewMin = cb
+	}
+}
+
+// WithMetrics enables the counters that cost extra work on every operation,
+// currently the number of item comparisons reported by Metrics.
+func WithMetrics() Option {
+	return func(p *PairHeap) {
+		p.countComparisons = true
+	}
+}
+
+// WithMaxTracking mirrors the items in a second, max-ordered pairing heap so
+// that FindMax is O(1) and DeleteMax O(log n) amortized, turning the PairHeap
+// into a double-ended priority queue. Every insertion and removal updates
+// both heaps, roughly doubling their cost.
+func WithMaxTracking() Option {
+	return func(p *PairHeap) {
+		p.trackMax = true
+	}
+}
+
+// SpillThreshold lets Spill move the largest items beyond the first
+// threshold ones out of memory, writing each through encode to w.
+// A negative threshold spills every item like zero does.
+func SpillThreshold(threshold int, w io.Writer, encode func(item heap.Item) ([]byte, error)) Option {
+	return func(p *PairHeap) {
+		p.spill = &spill{threshold: threshold, w: w, encode: encode}
+	}
+}
+
+// WithClock times Insert, DeleteMin and Delete using now as the clock and
+// accumulates the results reported by Timings. Heaps created without it pay
+// no timing overhead.
+func WithClock(now func() time.Time) Option {
+	return func(p *PairHeap) {
+		p.clock = now
+	}
+}
@@ -0,0 +1,97 @@
+package pairing
+
+import (
+	heap "github.com/theodesp/go-heaps"
+)
+
+// ShardedHeap partitions its items over K PairHeaps using a shard function
+// and presents them as a single heap.
+//
+// Structure is not thread safe.
+type ShardedHeap struct {
+	shards []*PairHeap
+	shard  func(item heap.Item) int
+}
+
+// NewSharded returns a ShardedHeap with k shards. The shard function
+// must return an index in [0, k) for every item inserted.
+func NewSharded(k int, shard func(item heap.Item) int) *ShardedHeap {
+	s := &ShardedHeap{shards: make([]*PairHeap, k), shard: shard}
+	for i := range s.shards {
+		s.shards[i] = New()
+	}
+	return s
+}
+
+// IsEmpty returns true if no shard holds any item.
+// The complexity is O(K).
+func (s *ShardedHeap) IsEmpty() bool {
+	for _, p := range s.shards {
+		if !p.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Size returns the number of items across all shards.
+// The complexity is O(K).
+func (s *ShardedHeap) Size() int {
+	size := 0
+	for _, p := range s.shards {
+		size += p.Size()
+	}
+	return size
+}
+
+// Resets all the shards
+func (s *ShardedHeap) Clear() {
+	for _, p := range s.shards {
+		p.Clear()
+	}
+}
+
+// Shard returns the PairHeap holding the i-th shard.
+func (s *ShardedHeap) Shard(i int) *PairHeap {
+	return s.shards[i]
+}
+
+// Inserts the value to its shard and returns the item
+// The complexity is O(1).
+func (s *ShardedHeap) Insert(v heap.Item) heap.Item {
+	return s.shards[s.shard(v)].Insert(v)
+}
+
+// Find the smallest item across all the shards.
+// The complexity is O(K).
+func (s *ShardedHeap) FindMin() heap.Item {
+	p := s.minShard()
+	if p == nil {
+		return nil
+	}
+	return p.FindMin()
+}
+
+// DeleteMin removes the smallest item across all the shards and returns it
+// The complexity is O(K + log n) amortized.
+func (s *ShardedHeap) DeleteMin() heap.Item {
+	p := s.minShard()
+	if p == nil {
+		return nil
+	}
+	return p.DeleteMin()
+}
+
+// minShard returns the shard holding the smallest item or nil if all are empty
+func (s *ShardedHeap) minShard() *PairHeap {
+	var min *PairHeap
+	for _, p := range s.shards {
+		if p.IsEmpty() {
+			continue
+		}
+		if min == nil || p.FindMin().Compare(min.FindMin()) < 0 {
+			min = p
+		}
+	}
+	return min
+}
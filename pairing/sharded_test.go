@@ -0,0 +1,34 @@
+package pairing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theodesp/go-heaps"
+)
+
+func TestShardedHeap(t *testing.T) {
+	// shard by range of values
+	s := NewSharded(3, func(item go_heaps.Item) int {
+		return int(item.(go_heaps.Integer)) / 10
+	})
+	assert.True(t, s.IsEmpty())
+	assert.Nil(t, s.FindMin())
+	assert.Nil(t, s.DeleteMin())
+
+	var h go_heaps.Interface = s
+	for _, v := range []int{25, 3, 14, 21, 7, 18, 1, 29, 10} {
+		h.Insert(Int(v))
+	}
+	assert.Equal(t, 9, s.Size())
+	assert.Equal(t, 3, s.Shard(0).Size())
+	assert.Equal(t, 3, s.Shard(1).Size())
+	assert.Equal(t, 3, s.Shard(2).Size())
+	assert.Equal(t, Int(1), s.FindMin())
+
+	var got []int
+	for !s.IsEmpty() {
+		got = append(got, int(s.DeleteMin().(go_heaps.Integer)))
+	}
+	assert.Equal(t, []int{1, 3, 7, 10, 14, 18, 21, 25, 29}, got)
+}
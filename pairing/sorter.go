@@ -0,0 +1,40 @@
+package pairing
+
+import (
+	heap "github.com/theodesp/go-heaps"
+)
+
+// LazySorter gives indexed access to the sorted order of a PairHeap,
+// popping from a private copy only as far as requested and caching the
+// items already popped.
+//
+// Structure is not thread safe.
+type LazySorter struct {
+	heap   *PairHeap
+	sorted []heap.Item
+}
+
+// NewLazySorter returns a LazySorter over a snapshot of p.
+// Later changes to p are not reflected.
+func NewLazySorter(p *PairHeap) *LazySorter {
+	c := p.clone()
+	return &LazySorter{heap: c, sorted: make([]heap.Item, 0, c.Size())}
+}
+
+// Len returns the total number of items.
+func (l *LazySorter) Len() int {
+	return len(l.sorted) + l.heap.Size()
+}
+
+// At returns the i-th smallest item, or nil if i is out of range.
+// The complexity is O(1) for cached indices and O(log n) amortized for
+// every item popped to reach i.
+func (l *LazySorter) At(i int) heap.Item {
+	if i < 0 || i >= l.Len() {
+		return nil
+	}
+	for len(l.sorted) <= i {
+		l.sorted = append(l.sorted, l.heap.DeleteMin())
+	}
+	return l.sorted[i]
+}
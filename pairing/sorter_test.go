@@ -0,0 +1,30 @@
+package pairing
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazySorter(t *testing.T) {
+	p := New()
+	values := rand.New(rand.NewSource(1)).Perm(50)
+	for _, v := range values {
+		p.Insert(Int(v))
+	}
+	sort.Ints(values)
+
+	l := NewLazySorter(p)
+	assert.Equal(t, 50, l.Len())
+	for _, i := range []int{10, 3, 10, 0, 49, 25, 11} {
+		assert.Equal(t, Int(values[i]), l.At(i))
+	}
+	assert.Nil(t, l.At(-1))
+	assert.Nil(t, l.At(50))
+
+	// the source heap is untouched
+	assert.Equal(t, 50, p.Size())
+	assert.Equal(t, Int(0), p.FindMin())
+}
@@ -0,0 +1,79 @@
+package pairing
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	heap "github.com/theodesp/go-heaps"
+)
+
+// spill holds the SpillThreshold configuration
+type spill struct {
+	threshold int
+	w         io.Writer
+	encode    func(item heap.Item) ([]byte, error)
+}
+
+// Spill writes the largest items to the writer configured by SpillThreshold
+// until the heap holds no more than threshold items, and returns how many
+// items were written. The items can be read back with Unspill.
+// The complexity is O(n) per spilled item, or O(log n) amortized
+// WithMaxTracking.
+func (p *PairHeap) Spill() (int, error) {
+	if p.spill == nil {
+		return 0, errors.New("pairing: spilling is not configured")
+	}
+	var frame [binary.MaxVarintLen64]byte
+	count := 0
+	for p.size > p.spill.threshold && !p.IsEmpty() {
+		n := p.maxNode()
+		data, err := p.spill.encode(n.item)
+		if err != nil {
+			return count, err
+		}
+		l := binary.PutUvarint(frame[:], uint64(len(data)))
+		if _, err := p.spill.w.Write(frame[:l]); err != nil {
+			return count, err
+		}
+		if _, err := p.spill.w.Write(data); err != nil {
+			return count, err
+		}
+		p.remove(n)
+		p.metrics.Evictions++
+		count++
+	}
+	return count, nil
+}
+
+// Unspill reads the items written by Spill from r, decodes them and inserts
+// them back into the heap. It returns the number of items inserted.
+// The complexity is O(1) per item.
+func (p *PairHeap) Unspill(r io.Reader, decode func(data []byte) (heap.Item, error)) (int, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		buffered := bufio.NewReader(r)
+		br, r = buffered, buffered
+	}
+	count := 0
+	for {
+		l, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		data, err := readData(r, l)
+		if err != nil {
+			return count, err
+		}
+		item, err := decode(data)
+		if err != nil {
+			return count, err
+		}
+		p.Insert(item)
+		count++
+	}
+}
@@ -0,0 +1,79 @@
+package pairing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/theodesp/go-heaps"
+)
+
+func encodeInt(item go_heaps.Item) ([]byte, error) {
+	return []byte(strconv.Itoa(int(item.(go_heaps.Integer)))), nil
+}
+
+func decodeInt(data []byte) (go_heaps.Item, error) {
+	v, err := strconv.Atoi(string(data))
+	return Int(v), err
+}
+
+func TestSpill(t *testing.T) {
+	_, err := New().Spill()
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+	p := New(SpillThreshold(3, &buf, encodeInt))
+	for _, v := range []int{40, 7, 123, 2, 19, 8, 1000, 5} {
+		p.Insert(Int(v))
+	}
+
+	spilled, err := p.Spill()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, spilled)
+	assert.Equal(t, 3, p.Size())
+	assert.Equal(t, 5, p.Metrics().Evictions)
+	assert.Equal(t, Int(7), p.FindMax())
+
+	spilled, err = p.Spill()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, spilled)
+
+	sorted, _ := p.Page(3)
+	read, err := p.Unspill(&buf, decodeInt)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, read)
+	rest, _ := p.Page(5)
+	assert.Equal(t, []go_heaps.Item{Int(2), Int(5), Int(7), Int(8), Int(19), Int(40), Int(123), Int(1000)}, append(sorted, rest...))
+}
+
+func TestSpillNegativeThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(SpillThreshold(-1, &buf, encodeInt))
+	p.Insert(Int(3))
+	p.Insert(Int(1))
+
+	spilled, err := p.Spill()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, spilled)
+	assert.True(t, p.IsEmpty())
+	assert.Equal(t, 0, p.Size())
+
+	read, err := p.Unspill(&buf, decodeInt)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, read)
+	assert.Equal(t, 2, p.Size())
+}
+
+func TestUnspillCorrupt(t *testing.T) {
+	// one item, then a frame claiming far more data than there is
+	data := binary.AppendUvarint(nil, 1)
+	data = append(data, '4')
+	data = binary.AppendUvarint(data, 1<<40)
+	p := New()
+	read, err := p.Unspill(bytes.NewReader(append(data, '2')), decodeInt)
+	assert.Error(t, err)
+	assert.Equal(t, 1, read)
+	assert.Equal(t, Int(4), p.FindMin())
+}
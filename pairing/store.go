@@ -0,0 +1,215 @@
+package pairing
+
+import (
+	heap "github.com/theodesp/go-heaps"
+)
+
+// NodeID identifies a node kept in a NodeStore. The zero NodeID is never
+// used for a node.
+type NodeID uint64
+
+// StoredNode is the form in which a heap node is kept in a NodeStore.
+type StoredNode struct {
+	Item     heap.Item
+	Children []NodeID
+}
+
+// NodeStore keeps the nodes of a StoreHeap, e.g. on disk or in a key-value
+// store for heaps too large to fit in memory.
+type NodeStore interface {
+	Get(id NodeID) (StoredNode, error)
+	Put(id NodeID, n StoredNode) error
+	Delete(id NodeID) error
+}
+
+// StoreHeap is a Pairing Heap whose nodes live in a NodeStore and refer to
+// each other by id. Only the root id, the size and the next free id are held
+// in memory; every node access goes through the store and returns its errors.
+// An operation failing in the store leaves the heap unchanged.
+//
+// Structure is not thread safe.
+type StoreHeap struct {
+	store NodeStore
+	root  NodeID
+	next  NodeID
+	size  int
+}
+
+// NewStoreHeap returns an empty StoreHeap keeping its nodes in store.
+func NewStoreHeap(store NodeStore) *StoreHeap {
+	return &StoreHeap{store: store, next: 1}
+}
+
+// IsEmpty returns true if StoreHeap s is empty.
+// The complexity is O(1).
+func (s *StoreHeap) IsEmpty() bool {
+	return s.root == 0
+}
+
+// Size returns the number of items in the StoreHeap.
+// The complexity is O(1).
+func (s *StoreHeap) Size() int {
+	return s.size
+}
+
+// Find the smallest item in the StoreHeap, or nil if it is empty.
+// The complexity is O(1) store accesses.
+func (s *StoreHeap) FindMin() (heap.Item, error) {
+	if s.IsEmpty() {
+		return nil, nil
+	}
+	n, err := s.store.Get(s.root)
+	return n.Item, err
+}
+
+// Inserts the value to the StoreHeap.
+// The complexity is O(1) store accesses.
+func (s *StoreHeap) Insert(v heap.Item) error {
+	tx := s.begin()
+	id := s.next
+	tx.add(id, StoredNode{Item: v})
+	root, err := tx.merge(s.root, id)
+	if err == nil {
+		err = tx.commit()
+	}
+	if err != nil {
+		return err
+	}
+	s.next++
+	s.root = root
+	s.size++
+	return nil
+}
+
+// DeleteMin removes the smallest item from the StoreHeap and returns it,
+// or nil if it is empty.
+// The complexity is O(log n) amortized store accesses.
+func (s *StoreHeap) DeleteMin() (heap.Item, error) {
+	if s.IsEmpty() {
+		return nil, nil
+	}
+	tx := s.begin()
+	n, err := tx.get(s.root)
+	if err != nil {
+		return nil, err
+	}
+	root, err := tx.mergePairs(n.Children)
+	if err == nil {
+		err = tx.commit()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Delete(s.root); err != nil {
+		tx.rollback(len(tx.dirty))
+		return nil, err
+	}
+	s.root = root
+	s.size--
+	return n.Item, nil
+}
+
+// storeTx stages the nodes changed by one StoreHeap operation in memory, so
+// that a failing store access leaves the stored tree as it was
+type storeTx struct {
+	store NodeStore
+	nodes map[NodeID]StoredNode
+	// the nodes as they were in the store, nil for new ones
+	orig  map[NodeID]*StoredNode
+	dirty []NodeID
+}
+
+func (s *StoreHeap) begin() *storeTx {
+	return &storeTx{store: s.store, nodes: map[NodeID]StoredNode{}, orig: map[NodeID]*StoredNode{}}
+}
+
+// get returns node id, reading it from the store on first access
+func (t *storeTx) get(id NodeID) (StoredNode, error) {
+	if n, ok := t.nodes[id]; ok {
+		return n, nil
+	}
+	n, err := t.store.Get(id)
+	if err != nil {
+		return n, err
+	}
+	t.nodes[id] = n
+	return n, nil
+}
+
+// add stages a new node
+func (t *storeTx) add(id NodeID, n StoredNode) {
+	t.nodes[id] = n
+	t.orig[id] = nil
+	t.dirty = append(t.dirty, id)
+}
+
+// put stages a change to node id
+func (t *storeTx) put(id NodeID, n StoredNode) {
+	if _, ok := t.orig[id]; !ok {
+		prev := t.nodes[id]
+		t.orig[id] = &prev
+		t.dirty = append(t.dirty, id)
+	}
+	t.nodes[id] = n
+}
+
+// commit writes the staged nodes to the store, undoing the writes already
+// done if one of them fails
+func (t *storeTx) commit() error {
+	for i, id := range t.dirty {
+		if err := t.store.Put(id, t.nodes[id]); err != nil {
+			t.rollback(i)
+			return err
+		}
+	}
+	return nil
+}
+
+// rollback restores the first count staged nodes in the store. It is best
+// effort: a store failing again cannot be helped.
+func (t *storeTx) rollback(count int) {
+	for _, id := range t.dirty[:count] {
+		if orig := t.orig[id]; orig != nil {
+			t.store.Put(id, *orig)
+		} else {
+			t.store.Delete(id)
+		}
+	}
+}
+
+// merge links the trees rooted at first and second and returns the new root
+func (t *storeTx) merge(first, second NodeID) (NodeID, error) {
+	if first == 0 {
+		return second, nil
+	}
+	q, err := t.get(first)
+	if err != nil {
+		return 0, err
+	}
+	r, err := t.get(second)
+	if err != nil {
+		return 0, err
+	}
+	if q.Item.Compare(r.Item) < 0 {
+		// put 'second' as the first child of 'first'
+		q.Children = append([]NodeID{second}, q.Children...)
+		t.put(first, q)
+		return first, nil
+	}
+	// put 'first' as the first child of 'second'
+	r.Children = append([]NodeID{first}, r.Children...)
+	t.put(second, r)
+	return second, nil
+}
+
+// mergePairs merges the trees rooted at ids together and returns the new root
+func (t *storeTx) mergePairs(ids []NodeID) (NodeID, error) {
+	var merged NodeID
+	for _, id := range ids { // iteratively merge heaps
+		var err error
+		if merged, err = t.merge(merged, id); err != nil {
+			return 0, err
+		}
+	}
+	return merged, nil
+}
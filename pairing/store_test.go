@@ -0,0 +1,123 @@
+package pairing
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mapStore is a NodeStore keeping the nodes in a map
+type mapStore map[NodeID]StoredNode
+
+func (m mapStore) Get(id NodeID) (StoredNode, error) {
+	n, ok := m[id]
+	if !ok {
+		return n, errors.New("node not found")
+	}
+	// hand out a copy like a real store would
+	n.Children = append([]NodeID(nil), n.Children...)
+	return n, nil
+}
+
+func (m mapStore) Put(id NodeID, n StoredNode) error {
+	m[id] = n
+	return nil
+}
+
+func (m mapStore) Delete(id NodeID) error {
+	delete(m, id)
+	return nil
+}
+
+// failingStore is a mapStore whose Put fails once failIn more Puts are made
+type failingStore struct {
+	mapStore
+	failIn int
+}
+
+func (f *failingStore) Put(id NodeID, n StoredNode) error {
+	if f.failIn--; f.failIn == 0 {
+		return errors.New("put failed")
+	}
+	return f.mapStore.Put(id, n)
+}
+
+func TestStoreHeap(t *testing.T) {
+	store := mapStore{}
+	s := NewStoreHeap(store)
+	item, err := s.DeleteMin()
+	assert.Nil(t, item)
+	assert.NoError(t, err)
+
+	// mirror a random sequence of operations on an in-memory heap
+	rng := rand.New(rand.NewSource(9))
+	p := New()
+	for i := 0; i < 2000; i++ {
+		if rng.Intn(3) > 0 {
+			v := Int(rng.Intn(500))
+			p.Insert(v)
+			assert.NoError(t, s.Insert(v))
+		} else {
+			item, err := s.DeleteMin()
+			assert.NoError(t, err)
+			assert.Equal(t, p.DeleteMin(), item)
+		}
+		min, err := s.FindMin()
+		assert.NoError(t, err)
+		assert.Equal(t, p.FindMin(), min)
+		assert.Equal(t, p.Size(), s.Size())
+		assert.Len(t, store, s.Size())
+	}
+
+	for !p.IsEmpty() {
+		item, err := s.DeleteMin()
+		assert.NoError(t, err)
+		assert.Equal(t, p.DeleteMin(), item)
+	}
+	assert.True(t, s.IsEmpty())
+	assert.Empty(t, store)
+}
+
+func TestStoreHeapError(t *testing.T) {
+	store := mapStore{}
+	s := NewStoreHeap(store)
+	assert.NoError(t, s.Insert(Int(1)))
+	assert.NoError(t, s.Insert(Int(2)))
+
+	delete(store, s.root)
+	_, err := s.FindMin()
+	assert.Error(t, err)
+	assert.Error(t, s.Insert(Int(0)))
+	_, err = s.DeleteMin()
+	assert.Error(t, err)
+}
+
+func TestStoreHeapFailedPut(t *testing.T) {
+	store := &failingStore{mapStore: mapStore{}}
+	s := NewStoreHeap(store)
+	for v := 1; v <= 5; v++ {
+		assert.NoError(t, s.Insert(Int(v)))
+	}
+
+	// the second Put links 4 below 3 after 5 was linked below 4
+	store.failIn = 2
+	_, err := s.DeleteMin()
+	assert.Error(t, err)
+	assert.Equal(t, 5, s.Size())
+
+	// the second Put adds 6 to the children of the root
+	store.failIn = 2
+	assert.Error(t, s.Insert(Int(6)))
+	assert.Equal(t, 5, s.Size())
+	assert.Len(t, store.mapStore, 5)
+
+	for v := 1; v <= 5; v++ {
+		item, err := s.DeleteMin()
+		assert.NoError(t, err)
+		assert.Equal(t, Int(v), item)
+	}
+	assert.True(t, s.IsEmpty())
+	assert.Empty(t, store.mapStore)
+}
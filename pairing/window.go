@@ -0,0 +1,51 @@
+package pairing
+
+import (
+	"time"
+
+	heap "github.com/theodesp/go-heaps"
+)
+
+// WindowMin tracks the minimum of the items added within a sliding
+// time window. Expired items are evicted lazily when queried.
+//
+// Structure is not thread safe.
+type WindowMin struct {
+	heap   *PairHeap
+	window time.Duration
+}
+
+// entry is an item stamped with the time it was added
+type entry struct {
+	item  heap.Item
+	added time.Time
+}
+
+func (e entry) Compare(than heap.Item) int {
+	return e.item.Compare(than.(entry).item)
+}
+
+// NewWindowMin returns a WindowMin over the given window duration.
+func NewWindowMin(window time.Duration) *WindowMin {
+	return &WindowMin{heap: New(), window: window}
+}
+
+// Add records item as added at time t.
+// The complexity is O(1).
+func (w *WindowMin) Add(item heap.Item, t time.Time) {
+	w.heap.Insert(entry{item: item, added: t})
+}
+
+// Min returns the smallest item added within the window ending at now,
+// or nil if there is none.
+// The complexity is O(log n) amortized per evicted item.
+func (w *WindowMin) Min(now time.Time) heap.Item {
+	for !w.heap.IsEmpty() {
+		e := w.heap.FindMin().(entry)
+		if now.Sub(e.added) <= w.window {
+			return e.item
+		}
+		w.heap.DeleteMin()
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package pairing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowMin(t *testing.T) {
+	start := time.Unix(0, 0)
+	at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+
+	w := NewWindowMin(10 * time.Second)
+	assert.Nil(t, w.Min(at(0)))
+
+	w.Add(Int(5), at(0))
+	w.Add(Int(8), at(3))
+	w.Add(Int(2), at(6))
+	w.Add(Int(9), at(12))
+
+	assert.Equal(t, Int(2), w.Min(at(12)))
+	assert.Equal(t, Int(2), w.Min(at(16)))
+	// 2, 5 and 8 have all expired by now
+	assert.Equal(t, Int(9), w.Min(at(17)))
+	assert.Nil(t, w.Min(at(30)))
+}